@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fixtures/fixtures"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// checkpointVersion is the version of the checkpoint format written, which
+// can read any checkpoint of the same or an earlier version.
+const checkpointVersion = 1
+
+// checkpoint is the header line of a breakpoint or population file, which
+// records the search that wrote it so that it is only resumed by the same
+// search. The positions it holds index each week's arrangements in the
+// order of its Enumeration, which a header written before it was recorded
+// does not give.
+type checkpoint struct {
+	Version     int                    `json:"version"`
+	Season      string                 `json:"season"`
+	Enumeration string                 `json:"enumeration,omitempty"`
+	Scoring     fixtures.ScoringConfig `json:"scoring"`
+	Best        string                 `json:"best,omitempty"`
+}
+
+// seasonHash is the SHA-256 hash of the season file being searched.
+var seasonHash string
+
+func hashFile(name string) (string, error) {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func currentCheckpoint() checkpoint {
+	answer := checkpoint{Version: checkpointVersion, Season: seasonHash, Enumeration: enumeration, Scoring: scoringConfig}
+	if best := getBestScore(); best != nil {
+		answer.Best = best.String()
+	}
+	return answer
+}
+
+// formatCheckpoint returns the header line followed by the body.
+func formatCheckpoint(header checkpoint, body []byte) ([]byte, error) {
+	line, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	buffer.Write(line)
+	buffer.WriteString("\n")
+	buffer.Write(body)
+	return buffer.Bytes(), nil
+}
+
+// parseCheckpoint returns the header and body of a checkpoint, checking
+// that it was written for the season and enumeration expected. A file written before
+// checkpoints had a header is returned whole, with no header.
+func parseCheckpoint(data []byte, expected checkpoint) (*checkpoint, []byte, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return nil, data, nil
+	}
+	line, body := data, []byte{}
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		line, body = data[:i], data[i+1:]
+	}
+	var header checkpoint
+	if err := json.Unmarshal(line, &header); err != nil {
+		return nil, nil, fmt.Errorf("header is not valid: %v", err)
+	}
+	if header.Version < 1 || header.Version > checkpointVersion {
+		return nil, nil, fmt.Errorf("version %d is not supported", header.Version)
+	}
+	if header.Season != expected.Season {
+		return nil, nil, fmt.Errorf("it belongs to a different fixture list")
+	}
+	if header.Enumeration != "" && header.Enumeration != expected.Enumeration {
+		return nil, nil, fmt.Errorf("it was written enumerating %s, not %s", header.Enumeration, expected.Enumeration)
+	}
+	return &header, body, nil
+}
+
+func writeCheckpoint(name string, body []byte) error {
+	data, err := formatCheckpoint(currentCheckpoint(), body)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(name, data)
+}
+
+// readCheckpoint returns the body of a checkpoint file, and whether it was
+// found. It stops the program if the checkpoint cannot be resumed.
+func readCheckpoint(name string) ([]byte, bool) {
+	data, read := readFile(name)
+	if !read {
+		return nil, false
+	}
+	expected := currentCheckpoint()
+	header, body, err := parseCheckpoint(data, expected)
+	if err != nil {
+		log.Fatalf("File %s cannot be resumed: %v", name, err)
+	}
+	if header == nil {
+		log.Printf("File %s has no checkpoint header, so it cannot be checked against the fixture list", name)
+		return body, true
+	}
+	if !sameScoring(header.Scoring, expected.Scoring) {
+		log.Printf("File %s was written with different scoring criteria", name)
+	}
+	if header.Best != "" {
+		log.Printf("File %s was written when the best score was %s", name, header.Best)
+	}
+	return body, true
+}
+
+func sameScoring(a, b fixtures.ScoringConfig) bool {
+	aj, _ := json.Marshal(a)
+	bj, _ := json.Marshal(b)
+	return bytes.Equal(aj, bj)
+}
+
+// writeFileAtomic writes the file by way of a temporary file in the same
+// directory, which is synced and then renamed, so that a crash leaves the
+// old file or the new one but never part of either.
+func writeFileAtomic(name string, data []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(name), filepath.Base(name)+".tmp")
+	if err != nil {
+		return err
+	}
+	temp := f.Name()
+	if _, err = f.Write(data); err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(temp, 0644)
+	}
+	if err == nil {
+		err = os.Rename(temp, name)
+	}
+	if err != nil {
+		os.Remove(temp)
+	}
+	return err
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fixtures/fixtures"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseCheckpoint(t *testing.T) {
+	header := checkpoint{Version: checkpointVersion, Season: "abc", Scoring: fixtures.DefaultScoringConfig(), Best: "164 164"}
+	data, err := formatCheckpoint(header, []byte("0 360: 12 0 5\n"))
+	assert.Nil(t, err)
+	parsed, body, err := parseCheckpoint(data, checkpoint{Season: "abc"})
+	assert.Nil(t, err)
+	assert.Equal(t, "0 360: 12 0 5\n", string(body))
+	assert.Equal(t, "164 164", parsed.Best)
+	assert.True(t, sameScoring(header.Scoring, parsed.Scoring))
+
+	parsed, body, err = parseCheckpoint([]byte("1 2 3\n"), checkpoint{Season: "abc"})
+	assert.Nil(t, err)
+	assert.Nil(t, parsed)
+	assert.Equal(t, "1 2 3\n", string(body))
+}
+
+func TestParseCheckpointNotValid(t *testing.T) {
+	data, _ := formatCheckpoint(checkpoint{Version: checkpointVersion, Season: "abc"}, []byte("1 2 3\n"))
+	_, _, err := parseCheckpoint(data, checkpoint{Season: "def"})
+	assert.EqualError(t, err, "it belongs to a different fixture list")
+	data, _ = formatCheckpoint(checkpoint{Version: checkpointVersion + 1, Season: "abc"}, nil)
+	_, _, err = parseCheckpoint(data, checkpoint{Season: "abc"})
+	assert.EqualError(t, err, "version 2 is not supported")
+	data, _ = formatCheckpoint(checkpoint{Version: checkpointVersion, Season: "abc", Enumeration: "pairings"}, nil)
+	_, _, err = parseCheckpoint(data, checkpoint{Season: "abc", Enumeration: "permutations"})
+	assert.EqualError(t, err, "it was written enumerating pairings, not permutations")
+	_, _, err = parseCheckpoint(data, checkpoint{Season: "abc", Enumeration: "pairings"})
+	assert.Nil(t, err)
+	_, _, err = parseCheckpoint([]byte("{\"version\": \n1 2 3\n"), checkpoint{Season: "abc"})
+	assert.NotNil(t, err)
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "breakpoint")
+	assert.Nil(t, writeFileAtomic(name, []byte("1 2 3\n")))
+	assert.Nil(t, writeFileAtomic(name, []byte("4 5 6\n")))
+	data, err := ioutil.ReadFile(name)
+	assert.Nil(t, err)
+	assert.Equal(t, "4 5 6\n", string(data))
+	files, _ := ioutil.ReadDir(dir)
+	assert.Equal(t, 1, len(files))
+	assert.Equal(t, "-rw-r--r--", files[0].Mode().String())
+	assert.NotNil(t, writeFileAtomic(filepath.Join(dir, "missing", "best"), nil))
+}
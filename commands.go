@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fixtures/fixtures"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+)
+
+var seasonFile = "season.json"
+var scoringFile = ""
+var outputDir = "."
+var mode = "exhaustive"
+var seed int64 = 0
+var populationSize = 100
+var populationInterval = 10
+var workers = runtime.NumCPU()
+var prune = true
+var enumeration = fixtures.Permutations.String()
+var checkpointInterval = 1000000
+var logInterval = 100000
+var calendarDir = "calendars"
+var format = fixtures.CSVFormat.String()
+
+type command struct {
+	name    string
+	args    string
+	summary string
+	minArgs int
+	maxArgs int
+	flags   []func(fs *flag.FlagSet)
+	run     func(args []string)
+}
+
+var commands = []command{
+	{
+		name:    "search",
+		summary: "search for the best arrangement of the season's fixtures",
+		flags:   []func(*flag.FlagSet){seasonFlags, scoringFlags, outputFlags, searchFlags, sinkFlags, serverFlags, solverFlags},
+		run:     search,
+	},
+	{
+		name:    "evaluate",
+		args:    "[schedule-file]",
+		summary: "check a schedule file, the best file if none is named, against the season and explain its score",
+		maxArgs: 1,
+		flags:   []func(*flag.FlagSet){seasonFlags, scoringFlags, outputFlags},
+		run: func(args []string) {
+			evaluateSchedule(seasonFile, argOrEmpty(args, 0))
+		},
+	},
+	{
+		name:    "export",
+		args:    "[schedule-file]",
+		summary: "write a schedule file, the best file if none is named, to standard output as text, CSV or JSON",
+		maxArgs: 1,
+		flags:   []func(*flag.FlagSet){seasonFlags, outputFlags, exportFlags},
+		run: func(args []string) {
+			exportSchedule(seasonFile, argOrEmpty(args, 0))
+		},
+	},
+	{
+		name:    "calendar",
+		args:    "[schedule-file]",
+		summary: "write a schedule file, the best file if none is named, as iCalendar files for the league and each team",
+		maxArgs: 1,
+		flags:   []func(*flag.FlagSet){seasonFlags, outputFlags, calendarFlags},
+		run: func(args []string) {
+			writeCalendars(seasonFile, argOrEmpty(args, 0))
+		},
+	},
+	{
+		name:    "validate",
+		summary: "check the season's fixture list and report every problem found",
+		flags:   []func(*flag.FlagSet){seasonFlags},
+		run: func(args []string) {
+			readFixtureList(seasonFile)
+		},
+	},
+	{
+		name:    "report",
+		args:    "[schedule-file...]",
+		summary: "explain the score of each schedule file, the best file if none is named",
+		maxArgs: -1,
+		flags:   []func(*flag.FlagSet){seasonFlags, scoringFlags, outputFlags},
+		run: func(args []string) {
+			reportSchedules(seasonFile, args)
+		},
+	},
+	{
+		name:    "generate",
+		args:    "league-file",
+		summary: "write to standard output the season generated from a league file",
+		minArgs: 1,
+		maxArgs: 1,
+		run: func(args []string) {
+			generateSeason(args[0])
+		},
+	},
+}
+
+func findCommand(name string) *command {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+func (c *command) flagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet(c.name, flag.ExitOnError)
+	for _, f := range c.flags {
+		f(fs)
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s [flags] %s\n%s.\n", os.Args[0], c.name, c.args, c.summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s command [flags] [arguments]\nCommands:\n", os.Args[0])
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-9s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintf(os.Stderr, "Run %s command -h for the flags of a command.\n", os.Args[0])
+}
+
+func argOrEmpty(args []string, i int) string {
+	if i < len(args) {
+		return args[i]
+	}
+	return ""
+}
+
+func seasonFlags(fs *flag.FlagSet) {
+	fs.StringVar(&seasonFile, "season", seasonFile, "JSON file holding the season's fixture list")
+}
+
+func scoringFlags(fs *flag.FlagSet) {
+	fs.StringVar(&scoringFile, "scoring", scoringFile, "JSON file describing the scoring criteria, or empty for the default scoring")
+}
+
+func outputFlags(fs *flag.FlagSet) {
+	fs.StringVar(&outputDir, "dir", outputDir, "directory holding the best, report, breakpoint and population files")
+}
+
+func searchFlags(fs *flag.FlagSet) {
+	fs.StringVar(&mode, "mode", mode, "search mode: exhaustive, anneal, genetic or milp")
+	fs.Int64Var(&seed, "seed", seed, "seed for the randomised search modes, or 0 to seed from the clock")
+	fs.IntVar(&populationSize, "population", populationSize, "number of schedules in each generation of the genetic search")
+	fs.IntVar(&populationInterval, "population-interval", populationInterval, "number of generations of the genetic search between writes of the population file")
+	fs.IntVar(&workers, "workers", workers, "number of search workers to run concurrently")
+	fs.BoolVar(&prune, "prune", prune, "skip partial schedules that cannot improve on the best score")
+	fs.StringVar(&enumeration, "enumeration", enumeration, "how each week's arrangements are enumerated: permutations; pairings, which at most halves the search by skipping mirror images when every timeslot fills the same courts; or pairings-ignoring-courts, which keeps courts in timeslot order and so is refused unless the court criterion has weight 0")
+	fs.IntVar(&checkpointInterval, "checkpoint-interval", checkpointInterval, "number of schedules evaluated between checkpoints of the search")
+	fs.IntVar(&logInterval, "log-interval", logInterval, "number of schedules evaluated between progress messages")
+}
+
+func exportFlags(fs *flag.FlagSet) {
+	fs.StringVar(&format, "format", format, "format in which to write the schedule: text, csv or json")
+}
+
+func calendarFlags(fs *flag.FlagSet) {
+	fs.StringVar(&calendarDir, "calendars", calendarDir, "directory to which the league and team .ics files are written")
+}
+
+func sinkFlags(fs *flag.FlagSet) {
+	fs.StringVar(&sinkName, "checkpoint", sinkName, "what is done with each checkpoint: file to leave it in the output directory, git to commit it, or snapshot to copy it to a snapshot directory")
+	fs.StringVar(&snapshotDir, "snapshots", snapshotDir, "directory holding the snapshots, or empty for the snapshots directory within the output directory")
+	fs.IntVar(&snapshotCount, "keep-snapshots", snapshotCount, "number of the latest snapshots to keep")
+}
+
+func serverFlags(fs *flag.FlagSet) {
+	fs.StringVar(&httpAddress, "http", httpAddress, "address, such as localhost:8080, at which to serve the status of the search and let it be controlled, or empty for none")
+}
@@ -0,0 +1,91 @@
+package fixtures
+
+import (
+	"math"
+	"math/rand"
+)
+
+type Annealer struct {
+	InitialTemperature float64
+	FinalTemperature   float64
+	StepsPerCycle      int
+	rng                *rand.Rand
+	scorer             Scorer
+	list               FixtureWeekList
+	weeks              []Schedule
+	score              int
+	best               Schedule
+	bestScore          int
+	step               int
+}
+
+func NewAnnealer(fl FixtureWeekList, scorer Scorer, seed int64) *Annealer {
+	rng := rand.New(rand.NewSource(seed))
+	weeks := make([]Schedule, len(fl))
+	for i, w := range fl {
+		weeks[i] = w.combination(rng.Intn(w.combinationCount))
+	}
+	a := &Annealer{
+		InitialTemperature: 50,
+		FinalTemperature:   0.5,
+		StepsPerCycle:      100000,
+		rng:                rng,
+		scorer:             scorer,
+		list:               fl,
+		weeks:              weeks,
+	}
+	current := a.current()
+	a.score = current.EvaluateWith(a.scorer)
+	a.best, a.bestScore = current, a.score
+	return a
+}
+
+func (a *Annealer) Step() bool {
+	w := a.rng.Intn(len(a.weeks))
+	week := a.weeks[w]
+	if len(week) < 2 {
+		return false
+	}
+	i := a.rng.Intn(len(week))
+	j := a.rng.Intn(len(week) - 1)
+	if j >= i {
+		j++
+	}
+	a.step++
+	a.swap(w, i, j)
+	current := a.current()
+	score := current.EvaluateWith(a.scorer)
+	if delta := score - a.score; delta > 0 && a.rng.Float64() >= math.Exp(-float64(delta)/a.Temperature()) {
+		a.swap(w, i, j)
+		return false
+	}
+	a.score = score
+	if score < a.bestScore {
+		a.best, a.bestScore = current, score
+		return true
+	}
+	return false
+}
+
+func (a *Annealer) Temperature() float64 {
+	progress := float64(a.step%a.StepsPerCycle) / float64(a.StepsPerCycle)
+	return a.InitialTemperature * math.Pow(a.FinalTemperature/a.InitialTemperature, progress)
+}
+
+func (a *Annealer) Best() (Schedule, int) {
+	return a.best, a.bestScore
+}
+
+func (a *Annealer) swap(w int, i int, j int) {
+	week := a.weeks[w]
+	week[i], week[j] = a.list[w].schedule(&week[j].Match, week[i].timeslot, week[i].court),
+		a.list[w].schedule(&week[i].Match, week[j].timeslot, week[j].court)
+}
+
+func (a *Annealer) current() Schedule {
+	answer := make(Schedule, 0, len(a.weeks)*10)
+	for _, w := range a.weeks {
+		answer = append(answer, w...)
+	}
+	return answer
+}
@@ -0,0 +1,73 @@
+package fixtures
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"testing"
+)
+
+func TestAnnealerIsReproducible(t *testing.T) {
+	run := func(seed int64) string {
+		a := NewAnnealer(buildFixtureList(t), DefaultScorer, seed)
+		a.StepsPerCycle = 500
+		for i := 0; i < 2000; i++ {
+			a.Step()
+		}
+		best, _ := a.Best()
+		return best.String()
+	}
+	assert.Equal(t, run(42), run(42))
+	assert.NotEqual(t, run(42), run(43))
+}
+
+func TestAnnealerImproves(t *testing.T) {
+	a := NewAnnealer(buildFixtureList(t), DefaultScorer, 1)
+	_, initial := a.Best()
+	improvements := 0
+	for i := 0; i < 5000; i++ {
+		if a.Step() {
+			improvements++
+		}
+	}
+	best, score := a.Best()
+	assert.True(t, improvements > 0)
+	assert.True(t, score < initial)
+	assert.Equal(t, score, best.Evaluate())
+}
+
+func TestAnnealerKeepsEveryMatch(t *testing.T) {
+	list := buildFixtureList(t)
+	a := NewAnnealer(list, DefaultScorer, 7)
+	for i := 0; i < 1000; i++ {
+		a.Step()
+	}
+	for i, w := range list {
+		matches := make([]string, 0, len(w.matches))
+		for _, m := range w.matches {
+			matches = append(matches, m.String())
+		}
+		slots := make(map[string]bool)
+		arranged := make([]string, 0, len(a.weeks[i]))
+		for j, sm := range a.weeks[i] {
+			assert.Equal(t, w.date, sm.date)
+			assert.Equal(t, w.timeslots[j], sm.timeslot)
+			assert.Equal(t, w.court(j), sm.court)
+			slots[sm.court+sm.timeslot.String()] = true
+			arranged = append(arranged, sm.Match.String())
+		}
+		sort.Strings(matches)
+		sort.Strings(arranged)
+		assert.Equal(t, matches, arranged)
+		assert.Equal(t, len(w.matches), len(slots))
+	}
+}
+
+func TestTemperature(t *testing.T) {
+	a := NewAnnealer(buildFixtureList(t), DefaultScorer, 1)
+	a.InitialTemperature, a.FinalTemperature, a.StepsPerCycle = 100, 1, 100
+	assert.InDelta(t, 100, a.Temperature(), 1e-9)
+	a.step = 50
+	assert.InDelta(t, 10, a.Temperature(), 1e-9)
+	a.step = 100
+	assert.InDelta(t, 100, a.Temperature(), 1e-9)
+}
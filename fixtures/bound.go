@@ -0,0 +1,142 @@
+package fixtures
+
+import (
+	"math/big"
+)
+
+type pruning struct {
+	remaining    []map[string]int
+	weeks        []Schedule
+	bounds       []int
+	boundedDepth int
+	pruned       *big.Int
+	pruneCount   int
+}
+
+func (it *FixtureListIterator) NextBounded(scorer Scorer, limit int) (Schedule, bool) {
+	for {
+		if it.nextIndices[0] >= it.last {
+			return nil, false
+		}
+		if limit < 0 {
+			return it.Next()
+		}
+		depth := it.prunableDepth(scorer, limit)
+		if depth < 0 {
+			return it.Next()
+		}
+		it.prune(depth)
+	}
+}
+
+func (it *FixtureListIterator) Pruned() *big.Int {
+	if it.pruned == nil {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Set(it.pruned)
+}
+
+func (it *FixtureListIterator) PruneCount() int {
+	return it.pruneCount
+}
+
+func (it *FixtureListIterator) prunableDepth(scorer Scorer, limit int) int {
+	if it.remaining == nil {
+		it.initPruning()
+	}
+	for d := 0; d < len(it.nextIndices)-1; d++ {
+		if d >= it.boundedDepth {
+			it.weeks[d] = (*it.list)[d].combination(it.nextIndices[d])
+			prefix := make(Schedule, 0, it.matchCount)
+			for _, w := range it.weeks[:d+1] {
+				prefix = append(prefix, w...)
+			}
+			it.bounds[d] = prefix.bound(scorer, it.remaining[d])
+			it.boundedDepth = d + 1
+		}
+		if it.bounds[d] >= limit {
+			return d
+		}
+	}
+	return -1
+}
+
+func (it *FixtureListIterator) prune(depth int) {
+	skipped := big.NewInt(1)
+	done := big.NewInt(0)
+	for i := depth + 1; i < len(it.nextIndices); i++ {
+		count := big.NewInt(int64(it.list.combinationCount(i)))
+		skipped.Mul(skipped, count)
+		done.Mul(done, count).Add(done, big.NewInt(int64(it.nextIndices[i])))
+		it.nextIndices[i] = 0
+	}
+	it.pruned.Add(it.pruned, skipped.Sub(skipped, done))
+	it.pruneCount++
+	it.incrementAt(depth)
+}
+
+func (it *FixtureListIterator) initPruning() {
+	weekCount := len(*it.list)
+	it.remaining = make([]map[string]int, weekCount)
+	remaining := make(map[string]int)
+	for d := weekCount - 1; d >= 0; d-- {
+		it.remaining[d] = make(map[string]int, len(remaining))
+		for t, c := range remaining {
+			it.remaining[d][t] = c
+		}
+		for _, m := range (*it.list)[d].matches {
+			remaining[m.team1]++
+			remaining[m.team2]++
+		}
+	}
+	it.weeks = make([]Schedule, weekCount)
+	it.bounds = make([]int, weekCount)
+	it.boundedDepth = 0
+	if it.pruned == nil {
+		it.pruned = big.NewInt(0)
+	}
+}
+
+func (s *Schedule) bound(scorer Scorer, remaining map[string]int) int {
+	answer := 0
+	for _, ts := range s.teamSchedules() {
+		if b := scorer.Bound(ts, remaining[ts.team]); b > answer {
+			answer = b
+		}
+	}
+	return answer
+}
+
+func imbalanceBound(counts []int, remaining int) int {
+	filled := append([]int{}, counts...)
+	answer := imbalanceOf(filled)
+	for r := 0; r < remaining; r++ {
+		lowest := 0
+		for i, c := range filled {
+			if c < filled[lowest] {
+				lowest = i
+			}
+		}
+		filled[lowest]++
+		if imbalance := imbalanceOf(filled); imbalance < answer {
+			answer = imbalance
+		}
+	}
+	return answer
+}
+
+func imbalanceOf(counts []int) int {
+	if len(counts) == 0 {
+		return 0
+	}
+	min, max := counts[0], counts[0]
+	for _, c := range counts {
+		if c < min {
+			min = c
+		}
+		if c > max {
+			max = c
+		}
+	}
+	return max - min
+}
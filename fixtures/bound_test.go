@@ -0,0 +1,85 @@
+package fixtures
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func TestImbalanceBound(t *testing.T) {
+	assert.Equal(t, 3, imbalanceBound([]int{0, 1, 3}, 0))
+	assert.Equal(t, 2, imbalanceBound([]int{0, 1, 3}, 1))
+	assert.Equal(t, 2, imbalanceBound([]int{0, 1, 3}, 2))
+	assert.Equal(t, 1, imbalanceBound([]int{0, 1, 3}, 3))
+	assert.Equal(t, 0, imbalanceBound([]int{0, 1, 3}, 5))
+	assert.Equal(t, 0, imbalanceBound([]int{2, 2}, 1))
+}
+
+func boundTestList() FixtureWeekList {
+	return FixtureWeekList{
+		NewWeek("31 May", 1, 2, false,
+			NewMatch("11", "12"),
+			NewMatch("13", "14"),
+			NewMatch("15", "16"),
+			NewMatch("17", "18")),
+		NewWeek("1 Jun", 6, 7, false,
+			NewMatch("11", "13"),
+			NewMatch("12", "15"),
+			NewMatch("14", "17"),
+			NewMatch("16", "18")),
+		NewWeek("2 Jun", 8, 9, false,
+			NewMatch("11", "14"),
+			NewMatch("12", "16"),
+			NewMatch("13", "18"),
+			NewMatch("15", "17")),
+	}
+}
+
+func TestPrefixBoundsNeverExceedScore(t *testing.T) {
+	list := boundTestList()
+	it := list.Iterator()
+	it.initPruning()
+	for s, ok := it.Next(); ok; s, ok = it.Next() {
+		score := s.Evaluate()
+		for d := 0; d < len(list); d++ {
+			prefix := s[:4*(d+1)]
+			assert.True(t, prefix.bound(DefaultScorer, it.remaining[d]) <= score)
+		}
+	}
+}
+
+func TestNextBoundedFindsTheOptimum(t *testing.T) {
+	list := boundTestList()
+	best := -1
+	it := list.Iterator()
+	for s, ok := it.Next(); ok; s, ok = it.Next() {
+		if score := s.Evaluate(); best == -1 || score < best {
+			best = score
+		}
+	}
+	boundedBest := -1
+	visited := 0
+	it = list.Iterator()
+	for s, ok := it.NextBounded(DefaultScorer, boundedBest); ok; s, ok = it.NextBounded(DefaultScorer, boundedBest) {
+		visited++
+		if score := s.Evaluate(); boundedBest == -1 || score < boundedBest {
+			boundedBest = score
+		}
+	}
+	assert.Equal(t, best, boundedBest)
+	assert.True(t, it.PruneCount() > 0)
+	total := big.NewInt(int64(visited))
+	assert.Equal(t, big.NewInt(24*24*24), total.Add(total, it.Pruned()))
+}
+
+func TestNextBoundedFromStartPosition(t *testing.T) {
+	list := boundTestList()
+	it := list.Iterator(3, 5, 7)
+	visited := 0
+	for _, ok := it.NextBounded(DefaultScorer, 1000); ok; _, ok = it.NextBounded(DefaultScorer, 0) {
+		visited++
+	}
+	assert.Equal(t, 1, visited)
+	total := big.NewInt(int64(visited))
+	assert.Equal(t, big.NewInt(24*24*24-(3*24*24+5*24+7)), total.Add(total, it.Pruned()))
+}
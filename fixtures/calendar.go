@@ -0,0 +1,171 @@
+package fixtures
+
+import (
+	"fmt"
+	"time"
+	_ "time/tzdata"
+)
+
+// DateFormat is how the league writes a date, such as "30 Sep", without
+// the year.
+const DateFormat = "2 Jan"
+
+// Season places the dates of a fixture list in the calendar. The first
+// week is in Year, and each later one in the year that puts it nearest
+// the week before, so that a season running from September to March moves
+// into the next year in January. A season file written before the year was
+// added has none, and its first week is then placed in the year that puts
+// it nearest today, which Validate warns about. Clock is how the season's
+// files write their times.
+type Season struct {
+	Year     int    `json:"year"`
+	TimeZone string `json:"timeZone,omitempty"`
+	Clock    Clock  `json:"clock,omitempty"`
+}
+
+func (s Season) location() (*time.Location, error) {
+	if s.TimeZone == "" {
+		return time.Local, nil
+	}
+	answer, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("time zone %q is not known", s.TimeZone)
+	}
+	return answer, nil
+}
+
+// isoDateFormat is how a date is exported, such as "2019-09-30".
+const isoDateFormat = "2006-01-02"
+
+// ParseDate reads a date written as the league writes it, with the month
+// in full, such as "30 September", or as isoDateFormat writes it. The year
+// is zero unless it is given, and InSeason takes no notice of it.
+func ParseDate(s string) (time.Time, error) {
+	for _, layout := range []string{DateFormat, "2 January", isoDateFormat} {
+		if answer, err := time.Parse(layout, s); err == nil {
+			return answer, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("date %q is not in the form 30 Sep", s)
+}
+
+// normaliseDate returns the date as DateFormat writes it.
+func normaliseDate(s string) (string, error) {
+	date, err := ParseDate(s)
+	if err != nil {
+		return "", err
+	}
+	return date.Format(DateFormat), nil
+}
+
+// InSeason returns a copy of the fixture list in which each week knows its
+// date in the calendar, and the dates are written as DateFormat writes
+// them.
+func (fl FixtureWeekList) InSeason(s Season) (FixtureWeekList, error) {
+	loc, err := s.location()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Clock.check(); err != nil {
+		return nil, err
+	}
+	answer := make(FixtureWeekList, len(fl))
+	var previous time.Time
+	for i, w := range fl {
+		date, err := ParseDate(w.date)
+		if err != nil {
+			return nil, fmt.Errorf("week %d: %v", i+1, err)
+		}
+		week := *w
+		week.guessedYear = i == 0 && s.Year == 0
+		week.clock = s.Clock
+		switch {
+		case week.guessedYear:
+			week.day = nearest(date, time.Now().In(loc))
+		case i == 0:
+			week.day = time.Date(s.Year, date.Month(), date.Day(), 0, 0, 0, 0, loc)
+		default:
+			week.day = nearest(date, previous)
+		}
+		week.date = week.day.Format(DateFormat)
+		previous = week.day
+		answer[i] = &week
+	}
+	return answer, nil
+}
+
+// nearest returns the day and month of the date in whichever year, of
+// the one before that of the previous date to the one after, puts it
+// nearest to the previous date.
+func nearest(date time.Time, previous time.Time) time.Time {
+	var answer time.Time
+	for year := previous.Year() - 1; year <= previous.Year()+1; year++ {
+		candidate := time.Date(year, date.Month(), date.Day(), 0, 0, 0, 0, previous.Location())
+		if answer.IsZero() || absDuration(candidate.Sub(previous)) < absDuration(answer.Sub(previous)) {
+			answer = candidate
+		}
+	}
+	return answer
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// Season returns the season in which the fixture list's dates were
+// placed, with no year if they were not.
+func (fl FixtureWeekList) Season() Season {
+	if len(fl) == 0 || fl[0].day.IsZero() {
+		return Season{}
+	}
+	answer := Season{Year: fl[0].day.Year(), Clock: fl[0].clock}
+	if loc := fl[0].day.Location(); loc != time.Local {
+		answer.TimeZone = loc.String()
+	}
+	return answer
+}
+
+// Start returns when the match starts, or the zero time if its week has
+// not been placed in the calendar.
+func (m *ScheduledMatch) Start() time.Time {
+	if m.day.IsZero() {
+		return time.Time{}
+	}
+	return time.Date(m.day.Year(), m.day.Month(), m.day.Day(), int(m.timeslot)/60, int(m.timeslot)%60, 0, 0, m.day.Location())
+}
+
+// End returns when the match finishes, or the zero time if its week has
+// not been placed in the calendar.
+func (m *ScheduledMatch) End() time.Time {
+	if m.day.IsZero() {
+		return time.Time{}
+	}
+	return m.Start().Add(time.Duration(m.duration) * time.Minute)
+}
+
+// Locate returns a copy of a schedule, such as one read by LoadSchedule,
+// in which each match is scheduled by the week of the fixture list on its
+// date, and so knows its day, how long it lasts and whether its teams are
+// available. The schedule's times are read on the season's clock.
+func (fl FixtureWeekList) Locate(s Schedule) (Schedule, error) {
+	weeks := make(map[string]*Week, len(fl))
+	for _, w := range fl {
+		weeks[w.date] = w
+	}
+	answer := make(Schedule, len(s))
+	for i, m := range s {
+		date, err := normaliseDate(m.date)
+		if err != nil {
+			return nil, fmt.Errorf("%s v %s: %v", m.team1, m.team2, err)
+		}
+		w, found := weeks[date]
+		if !found {
+			return nil, fmt.Errorf("%s v %s: no week on %s", m.team1, m.team2, m.date)
+		}
+		answer[i] = w.schedule(&m.Match, w.clock.convert(m.timeslot), m.court)
+	}
+	return answer, nil
+}
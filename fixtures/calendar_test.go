@@ -0,0 +1,138 @@
+package fixtures
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	for _, s := range []string{"30 Sep", "30 September", "30 sep", "2019-09-30"} {
+		date, err := ParseDate(s)
+		assert.Nil(t, err, s)
+		assert.Equal(t, time.September, date.Month())
+		assert.Equal(t, 30, date.Day())
+	}
+	for _, s := range []string{"", "Sep 30", "31 Sep", "30/9", "someday"} {
+		_, err := ParseDate(s)
+		assert.NotNil(t, err, s)
+	}
+	_, err := ParseDate("30/9")
+	assert.EqualError(t, err, "date \"30/9\" is not in the form 30 Sep")
+}
+
+func TestInSeason(t *testing.T) {
+	fl := buildFixtureList(t)
+	london, _ := time.LoadLocation("Europe/London")
+	assert.Equal(t, time.Date(2018, time.September, 30, 0, 0, 0, 0, london), fl[0].day)
+	assert.Equal(t, time.Date(2018, time.December, 16, 0, 0, 0, 0, london), fl[10].day)
+	assert.Equal(t, time.Date(2019, time.January, 6, 0, 0, 0, 0, london), fl[11].day)
+	assert.Equal(t, time.Date(2019, time.March, 17, 0, 0, 0, 0, london), fl[len(fl)-1].day)
+	assert.Equal(t, Season{Year: 2018, TimeZone: "Europe/London"}, fl.Season())
+	for _, w := range fl {
+		assert.Equal(t, time.Sunday, w.day.Weekday(), w.date)
+	}
+
+	list, err := FixtureWeekList{
+		NewWeek("1 June", 6, 6, false, NewMatch("11", "12")),
+		NewWeek("2 Jun", 6, 6, false, NewMatch("11", "12")),
+	}.InSeason(Season{Year: 2020})
+	assert.Nil(t, err)
+	assert.Equal(t, "1 Jun", list[0].date)
+	assert.Equal(t, time.Local, list[0].day.Location())
+	assert.Equal(t, Season{Year: 2020}, list.Season())
+	assert.Equal(t, Season{}, FixtureWeekList{NewWeek("1 Jun", 6, 6, false)}.Season())
+}
+
+func TestInSeasonNotValid(t *testing.T) {
+	list := FixtureWeekList{
+		NewWeek("1 Jun", 6, 6, false, NewMatch("11", "12")),
+		NewWeek("Tuesday", 6, 6, false, NewMatch("11", "12")),
+	}
+	_, err := list.InSeason(Season{Year: 2020, TimeZone: "Hampshire"})
+	assert.EqualError(t, err, "time zone \"Hampshire\" is not known")
+	_, err = list.InSeason(Season{Year: 2020})
+	assert.EqualError(t, err, "week 2: date \"Tuesday\" is not in the form 30 Sep")
+}
+
+func TestInSeasonWithNoYear(t *testing.T) {
+	list, err := FixtureWeekList{
+		NewWeek("1 Jun", 6, 6, false, NewMatch("11", "12"), NewMatch("13", "14")),
+		NewWeek("8 Jun", 6, 6, false, NewMatch("11", "13"), NewMatch("12", "14")),
+	}.InSeason(Season{})
+	assert.Nil(t, err)
+	assert.True(t, absDuration(time.Since(list[0].day)) < 183*24*time.Hour)
+	assert.Equal(t, 7, int(list[1].day.Sub(list[0].day).Hours()/24))
+	year := list[0].day.Year()
+	assert.Equal(t, Problems{{Warning, -1, "", fmt.Sprintf("the season has no year, so it is taken to start in %d; add \"year\": %d to the season file", year, year)}}, Validate(list))
+	assert.Equal(t, Season{Year: year}, list.Season())
+}
+
+func TestValidateDateOrder(t *testing.T) {
+	list, err := FixtureWeekList{
+		NewWeek("14 Oct", 6, 6, false, NewMatch("11", "12")),
+		NewWeek("30 Sep", 6, 6, false, NewMatch("13", "14")),
+		NewWeek("14 Oct", 6, 6, false, NewMatch("15", "16")),
+		NewWeek("6 Jan", 6, 6, false, NewMatch("17", "18")),
+	}.InSeason(Season{Year: 2019})
+	assert.Nil(t, err)
+	assert.Equal(t, 2020, list[3].day.Year())
+	assert.Equal(t, []string{
+		"warning: week 1 (14 Oct): the week contains only one match, so there is nothing to arrange",
+		"error: week 2 (30 Sep): the date is not after that of week 1 (14 Oct)",
+		"warning: week 2 (30 Sep): the week contains only one match, so there is nothing to arrange",
+		"error: week 3 (14 Oct): the date is also used by week 1",
+		"warning: week 3 (14 Oct): the week contains only one match, so there is nothing to arrange",
+		"warning: week 4 (6 Jan): the week contains only one match, so there is nothing to arrange",
+	}, problemStrings(Validate(list)))
+}
+
+func TestMatchStartAndEnd(t *testing.T) {
+	list, err := FixtureWeekList{
+		NewWeekWithTimes("27 Oct", []Time{At(19, 0), At(19, 40)}, false, []string{"A"}, NewMatch("11", "12"), NewMatch("13", "14")),
+	}.InSeason(Season{Year: 2019, TimeZone: "Europe/London"})
+	assert.Nil(t, err)
+	list[0].duration = 40
+	s := list.Combination(0)
+	london, _ := time.LoadLocation("Europe/London")
+	assert.Equal(t, time.Date(2019, time.October, 27, 19, 40, 0, 0, london), s[1].Start())
+	assert.Equal(t, time.Date(2019, time.October, 27, 20, 20, 0, 0, london), s[1].End())
+	assert.Equal(t, "2019-10-27T19:00:00Z", s[0].Start().UTC().Format(time.RFC3339))
+	morning, err := FixtureWeekList{
+		NewWeekWithTimes("27 Oct", []Time{At(9, 0)}, false, []string{"A"}, NewMatch("11", "12")),
+	}.InSeason(Season{Year: 2019, TimeZone: "Europe/London"})
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2019, time.October, 27, 9, 0, 0, 0, london), morning.Combination(0)[0].Start())
+	undated := FixtureWeekList{NewWeek("31 May", 6, 6, false, NewMatch("11", "12"))}.Combination(0)
+	assert.True(t, undated[0].Start().IsZero())
+	assert.True(t, undated[0].End().IsZero())
+}
+
+func TestLocate(t *testing.T) {
+	list := buildFixtureList(t).WithUnavailability(Unavailabilities{{Team: "25", Date: "30 Sep"}})
+	s, err := LoadSchedule(strings.NewReader("164\n30 Sep, 19.15, B: 25 v 26\n6 January, 18.15, A: 11 v 12\n"))
+	assert.Nil(t, err)
+	located, err := list.Locate(s)
+	assert.Nil(t, err)
+	assert.Equal(t, "30 Sep, 19.15, B: 25 v 26\n", located[0].String())
+	assert.Equal(t, [2]bool{true, false}, located[0].unavailable)
+	assert.Equal(t, 2019, located[1].Start().Year())
+	assert.Equal(t, 60, located[1].duration)
+	_, err = list.Locate(Schedule{NewScheduledMatch(NewMatch("11", "12"), "1 Jun", At(6, 15), "A")})
+	assert.EqualError(t, err, "11 v 12: no week on 1 Jun")
+}
+
+func TestLocateOnEveningClock(t *testing.T) {
+	list, err := buildFixtureList(t).InSeason(Season{Year: 2018, TimeZone: "Europe/London", Clock: EveningClock})
+	assert.Nil(t, err)
+	s, err := LoadSchedule(strings.NewReader("164\n30 Sep, 7.15, B: 25 v 26\n30 Sep, 19.15, A: 21 v 24\n"))
+	assert.Nil(t, err)
+	for _, p := range ValidateSchedule(list, s) {
+		assert.NotContains(t, p.Message, "timeslots")
+	}
+	located, err := list.Locate(s)
+	assert.Nil(t, err)
+	assert.Equal(t, "30 Sep, 19.15, B: 25 v 26\n30 Sep, 19.15, A: 21 v 24\n", located.String())
+}
@@ -3,7 +3,9 @@ package fixtures
 import (
 	"bytes"
 	"fmt"
+	"math/big"
 	"sort"
+	"time"
 )
 
 type Match struct {
@@ -24,16 +26,20 @@ func NewMatch(team1 string, team2 string) *Match {
 
 type ScheduledMatch struct {
 	Match
-	date     string
-	timeslot int
-	court    string
+	date        string
+	timeslot    Time
+	court       string
+	unavailable [2]bool
+	day         time.Time
+	duration    int
+	division    string
 }
 
 func (m *ScheduledMatch) String() string {
-	return fmt.Sprintf("%s, %d%s, %s: %s v %s\n", m.date, m.timeslot, ".15", m.court, m.team1, m.team2)
+	return fmt.Sprintf("%s, %v, %s: %s v %s\n", m.date, m.timeslot, m.court, m.team1, m.team2)
 }
 
-func NewScheduledMatch(m *Match, date string, timeslot int, court string) *ScheduledMatch {
+func NewScheduledMatch(m *Match, date string, timeslot Time, court string) *ScheduledMatch {
 	return &ScheduledMatch{
 		Match: Match{
 			m.team1,
@@ -46,10 +52,21 @@ func NewScheduledMatch(m *Match, date string, timeslot int, court string) *Sched
 }
 
 type Week struct {
-	date             string
-	timeslots        []int
-	matches          []*Match
-	combinationCount int
+	date              string
+	day               time.Time
+	guessedYear       bool
+	clock             Clock
+	timeslots         []Time
+	duration          int
+	courts            []string
+	matches           []*Match
+	combinationCount  int
+	enumeration       Enumeration
+	fixedCourts       bool
+	unavailable       Unavailabilities
+	seasonUnavailable Unavailabilities
+	divisions         []Division
+	divisionOf        map[string]string
 }
 
 func (w *Week) String() string {
@@ -62,6 +79,9 @@ func (w *Week) String() string {
 }
 
 func (w *Week) combination(comb int) Schedule {
+	if w.enumeration != Permutations {
+		return w.pairingCombination(comb)
+	}
 	matchCount := len(w.matches)
 	answer := make(Schedule, 0, matchCount)
 	remainingMatches := w.matches
@@ -69,7 +89,7 @@ func (w *Week) combination(comb int) Schedule {
 	for i := 0; i < matchCount; i++ {
 		var mi int
 		c, mi = divmod(c, len(remainingMatches))
-		answer = append(answer, NewScheduledMatch(remainingMatches[mi], w.date, w.timeslots[i], w.court(i)))
+		answer = append(answer, w.schedule(remainingMatches[mi], w.timeslots[i], w.court(i)))
 		remainingMatches = copyWithoutItemAt(remainingMatches, mi)
 	}
 	return answer[:]
@@ -80,24 +100,51 @@ func (w *Week) matchCount() int {
 }
 
 func (w *Week) court(index int) string {
-	if index == 0 || w.timeslots[index] != w.timeslots[index-1] {
-		return "A"
+	c := 0
+	for index-c > 0 && w.timeslots[index-c] == w.timeslots[index-c-1] {
+		c++
+	}
+	return w.courts[c]
+}
+
+func (w *Week) times() []Time {
+	answer := make([]Time, 0, len(w.timeslots))
+	for i, t := range w.timeslots {
+		if i == 0 || t != w.timeslots[i-1] {
+			answer = append(answer, t)
+		}
 	}
-	return "B"
+	return answer
 }
 
+var DefaultCourts = []string{"A", "B"}
+
 func NewWeek(date string, startTime int, endTime int, firstTimeSingle bool, matches ...*Match) *Week {
+	return NewWeekWithCourts(date, startTime, endTime, firstTimeSingle, DefaultCourts, matches...)
+}
+
+func NewWeekWithCourts(date string, startTime int, endTime int, firstTimeSingle bool, courts []string, matches ...*Match) *Week {
+	slots := Slots{Start: startTime, End: endTime}
+	return NewWeekWithTimes(date, slots.times(), firstTimeSingle, courts, matches...)
+}
+
+// NewWeekWithTimes creates a week whose timeslots each fill every court,
+// except the first when firstTimeSingle is set, which uses only the first.
+func NewWeekWithTimes(date string, times []Time, firstTimeSingle bool, courts []string, matches ...*Match) *Week {
 	matchCount := len(matches)
-	ts := make([]int, 0, matchCount)
-	for t := startTime; t <= endTime && len(ts) < matchCount; t++ {
-		ts = append(ts, t)
-		if (t != startTime || !firstTimeSingle) && len(ts) < matchCount {
-			ts = append(ts, t)
+	ts := make([]Time, 0, matchCount)
+	for i, t := range times {
+		for c := range courts {
+			if len(ts) < matchCount && (c == 0 || i > 0 || !firstTimeSingle) {
+				ts = append(ts, t)
+			}
 		}
 	}
 	return &Week{
 		date:             date,
 		timeslots:        ts,
+		duration:         DefaultDuration,
+		courts:           courts,
 		matches:          matches,
 		combinationCount: combinations(len(matches)),
 	}
@@ -105,7 +152,47 @@ func NewWeek(date string, startTime int, endTime int, firstTimeSingle bool, matc
 
 type FixtureWeekList []*Week
 
+// Courts returns every court used in the season, in the order first seen.
+func (fl FixtureWeekList) Courts() []string {
+	answer := make([]string, 0, len(DefaultCourts))
+	seen := make(map[string]bool)
+	for _, w := range fl {
+		for _, c := range w.courts {
+			if !seen[c] {
+				seen[c] = true
+				answer = append(answer, c)
+			}
+		}
+	}
+	return answer
+}
+
+func (fl FixtureWeekList) sameCourts() bool {
+	for _, w := range fl {
+		if !sameStrings(w.courts, fl[0].courts) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 func (fl *FixtureWeekList) Iterator(startIndices ...int) *FixtureListIterator {
+	return fl.RangeIterator(0, fl.combinationCount(0), startIndices...)
+}
+
+func (fl *FixtureWeekList) RangeIterator(first int, last int, startIndices ...int) *FixtureListIterator {
 	listLength := len(*fl)
 	nextIndices := mapSlice(copy(startIndices, listLength), func(i, v int) int {
 		if v >= fl.combinationCount(i) {
@@ -113,6 +200,11 @@ func (fl *FixtureWeekList) Iterator(startIndices ...int) *FixtureListIterator {
 		}
 		return v
 	})
+	if start := copy(startIndices, 1)[0]; start >= last {
+		nextIndices = copy([]int{last}, listLength)
+	} else if start < first {
+		nextIndices = copy([]int{first}, listLength)
+	}
 	matchCount := 0
 	for _, w := range *fl {
 		matchCount += len(w.matches)
@@ -121,22 +213,57 @@ func (fl *FixtureWeekList) Iterator(startIndices ...int) *FixtureListIterator {
 		list:        fl,
 		nextIndices: nextIndices,
 		matchCount:  matchCount,
+		first:       first,
+		last:        last,
 	}
 	return it
 }
 
+func (fl FixtureWeekList) Combination(indices ...int) Schedule {
+	answer := make(Schedule, 0, len(fl)*10)
+	for i, w := range fl {
+		answer = append(answer, w.combination(indices[i])...)
+	}
+	return answer
+}
+
 func (fl *FixtureWeekList) combinationCount(w int) int {
 	return (*fl)[w].combinationCount
 }
 
+// Size returns the number of schedules into which the fixture list can be
+// arranged.
+func (fl FixtureWeekList) Size() *big.Int {
+	if len(fl) == 0 {
+		return big.NewInt(0)
+	}
+	return fl.Rank(fl[0].combinationCount)
+}
+
+// Rank returns the number of schedules that an iterator visits before the
+// one with the indices given, taking any missing indices to be zero.
+func (fl FixtureWeekList) Rank(indices ...int) *big.Int {
+	answer := big.NewInt(0)
+	for i, w := range fl {
+		answer.Mul(answer, big.NewInt(int64(w.combinationCount)))
+		if i < len(indices) {
+			answer.Add(answer, big.NewInt(int64(indices[i])))
+		}
+	}
+	return answer
+}
+
 type FixtureListIterator struct {
 	list        *FixtureWeekList
 	nextIndices []int
 	matchCount  int
+	first       int
+	last        int
+	pruning
 }
 
 func (it *FixtureListIterator) Next() (Schedule, bool) {
-	if it.nextIndices[0] == it.list.combinationCount(0) {
+	if it.nextIndices[0] >= it.last {
 		return nil, false
 	}
 	matches := make(Schedule, 0, it.matchCount)
@@ -150,12 +277,19 @@ func (it *FixtureListIterator) Next() (Schedule, bool) {
 }
 
 func (it *FixtureListIterator) NextIndices() []int {
-	return it.nextIndices
+	return copy(it.nextIndices, len(it.nextIndices))
 }
 
 func (it *FixtureListIterator) increment() {
-	for i := len(it.nextIndices) - 1; i >= 0; i-- {
+	it.incrementAt(len(it.nextIndices) - 1)
+}
+
+func (it *FixtureListIterator) incrementAt(index int) {
+	for i := index; i >= 0; i-- {
 		it.nextIndices[i]++
+		if i < it.boundedDepth {
+			it.boundedDepth = i
+		}
 		if i == 0 || it.nextIndices[i] < it.list.combinationCount(i) {
 			break
 		}
@@ -199,9 +333,13 @@ func (s *Schedule) teamSchedules() []*TeamSchedule {
 }
 
 func (s *Schedule) Evaluate() int {
+	return s.EvaluateWith(DefaultScorer)
+}
+
+func (s *Schedule) EvaluateWith(scorer Scorer) int {
 	answer := 0
 	for _, ts := range s.teamSchedules() {
-		if score := ts.evaluate(); score > answer {
+		if score := scorer.Score(ts); score > answer {
 			answer = score
 		}
 	}
@@ -214,52 +352,7 @@ type TeamSchedule struct {
 }
 
 func (ts *TeamSchedule) evaluate() int {
-	timeCounts := make(map[interface{}]int)
-	for _, i := range []int{6, 7, 8, 9} {
-		timeCounts[i] = 0
-	}
-	courtCounts := make(map[interface{}]int)
-	for _, i := range []string{"A", "B"} {
-		courtCounts[i] = 0
-	}
-	updateCount := func(counts map[interface{}]int, value interface{}) {
-		_, found := counts[value]
-		if !found {
-			counts[value] = 0
-		}
-		counts[value]++
-	}
-	for _, m := range ts.matches {
-		updateCount(timeCounts, m.timeslot)
-		updateCount(courtCounts, m.court)
-	}
-	imbalance := func(counts map[interface{}]int) int {
-		min, max := -1, -1
-		for _, t := range counts {
-			if min == -1 || t < min {
-				min = t
-			}
-			if max == -1 || t > max {
-				max = t
-			}
-		}
-		if min == -1 {
-			return max
-		}
-		if max == -1 {
-			return min
-		}
-		return max - min
-	}
-	answer := 0
-	for i, t := range []int{5, 9} {
-		if c, ok := timeCounts[t]; ok && c > i+1 {
-			answer = 100
-			break
-		}
-	}
-	answer += 10*imbalance(timeCounts) + imbalance(courtCounts)
-	return answer
+	return DefaultScorer.Score(ts)
 }
 
 func findFirst(slice []int, predicate func(int, int) bool) int {
@@ -281,16 +374,16 @@ func findLast(slice []int, predicate func(int, int) bool) int {
 	return -1
 }
 
-func BuildFixtureList() FixtureWeekList {
+func BuildFixtureList() (FixtureWeekList, error) {
 	weeks := FixtureWeekList{
-		NewWeek("30 Sep", 6, 8, false,
+		NewWeek("30 Sep", 18, 20, false,
 			NewMatch("25", "26"),
 			NewMatch("21", "24"),
 			NewMatch("23", "22"),
 			NewMatch("15", "16"),
 			NewMatch("51", "52"),
 			NewMatch("41", "42")),
-		NewWeek("14 Oct", 6, 9, false,
+		NewWeek("14 Oct", 18, 21, false,
 			NewMatch("26", "21"),
 			NewMatch("22", "25"),
 			NewMatch("11", "14"),
@@ -299,7 +392,7 @@ func BuildFixtureList() FixtureWeekList {
 			NewMatch("53", "510"),
 			NewMatch("43", "410"),
 			NewMatch("33", "310")),
-		NewWeek("21 Oct", 6, 9, false,
+		NewWeek("21 Oct", 18, 21, false,
 			NewMatch("24", "23"),
 			NewMatch("59", "54"),
 			NewMatch("49", "44"),
@@ -308,7 +401,7 @@ func BuildFixtureList() FixtureWeekList {
 			NewMatch("45", "48"),
 			NewMatch("35", "38"),
 			NewMatch("57", "56")),
-		NewWeek("28 Oct", 6, 9, false,
+		NewWeek("28 Oct", 18, 21, false,
 			NewMatch("22", "26"),
 			NewMatch("16", "11"),
 			NewMatch("12", "15"),
@@ -317,7 +410,7 @@ func BuildFixtureList() FixtureWeekList {
 			NewMatch("510", "51"),
 			NewMatch("410", "41"),
 			NewMatch("310", "31")),
-		NewWeek("4 Nov", 6, 9, false,
+		NewWeek("4 Nov", 18, 21, false,
 			NewMatch("23", "21"),
 			NewMatch("25", "24"),
 			NewMatch("14", "13"),
@@ -326,7 +419,7 @@ func BuildFixtureList() FixtureWeekList {
 			NewMatch("32", "39"),
 			NewMatch("58", "53"),
 			NewMatch("48", "43")),
-		NewWeek("11 Nov", 6, 9, false,
+		NewWeek("11 Nov", 18, 21, false,
 			NewMatch("12", "16"),
 			NewMatch("38", "33"),
 			NewMatch("54", "57"),
@@ -335,7 +428,7 @@ func BuildFixtureList() FixtureWeekList {
 			NewMatch("56", "55"),
 			NewMatch("46", "45"),
 			NewMatch("36", "35")),
-		NewWeek("18 Nov", 5, 9, false,
+		NewWeek("18 Nov", 17, 21, false,
 			NewMatch("51", "59"),
 			NewMatch("41", "49"),
 			NewMatch("26", "23"),
@@ -346,7 +439,7 @@ func BuildFixtureList() FixtureWeekList {
 			NewMatch("510", "58"),
 			NewMatch("410", "48"),
 			NewMatch("310", "38")),
-		NewWeek("25 Nov", 6, 9, false,
+		NewWeek("25 Nov", 18, 21, false,
 			NewMatch("25", "21"),
 			NewMatch("57", "52"),
 			NewMatch("47", "42"),
@@ -355,7 +448,7 @@ func BuildFixtureList() FixtureWeekList {
 			NewMatch("43", "46"),
 			NewMatch("33", "36"),
 			NewMatch("55", "54")),
-		NewWeek("2 Dec", 5, 9, true,
+		NewWeek("2 Dec", 17, 21, true,
 			NewMatch("45", "44"),
 			NewMatch("24", "26"),
 			NewMatch("16", "13"),
@@ -365,7 +458,7 @@ func BuildFixtureList() FixtureWeekList {
 			NewMatch("48", "41"),
 			NewMatch("38", "31"),
 			NewMatch("59", "57")),
-		NewWeek("9 Dec", 6, 9, false,
+		NewWeek("9 Dec", 18, 21, false,
 			NewMatch("25", "23"),
 			NewMatch("22", "21"),
 			NewMatch("15", "11"),
@@ -374,7 +467,7 @@ func BuildFixtureList() FixtureWeekList {
 			NewMatch("56", "510"),
 			NewMatch("46", "410"),
 			NewMatch("36", "310")),
-		NewWeek("16 Dec", 6, 9, false,
+		NewWeek("16 Dec", 18, 21, false,
 			NewMatch("14", "16"),
 			NewMatch("52", "55"),
 			NewMatch("42", "45"),
@@ -383,7 +476,7 @@ func BuildFixtureList() FixtureWeekList {
 			NewMatch("44", "43"),
 			NewMatch("34", "33"),
 			NewMatch("51", "57")),
-		NewWeek("6 Jan", 6, 9, false,
+		NewWeek("6 Jan", 18, 21, false,
 			NewMatch("26", "25"),
 			NewMatch("24", "21"),
 			NewMatch("15", "13"),
@@ -392,7 +485,7 @@ func BuildFixtureList() FixtureWeekList {
 			NewMatch("31", "37"),
 			NewMatch("58", "56"),
 			NewMatch("48", "46")),
-		NewWeek("13 Jan", 6, 9, false,
+		NewWeek("13 Jan", 18, 21, false,
 			NewMatch("23", "22"),
 			NewMatch("38", "36"),
 			NewMatch("55", "59"),
@@ -401,7 +494,7 @@ func BuildFixtureList() FixtureWeekList {
 			NewMatch("510", "54"),
 			NewMatch("410", "44"),
 			NewMatch("310", "34")),
-		NewWeek("20 Jan", 6, 9, false,
+		NewWeek("20 Jan", 18, 21, false,
 			NewMatch("21", "26"),
 			NewMatch("16", "15"),
 			NewMatch("14", "11"),
@@ -410,7 +503,7 @@ func BuildFixtureList() FixtureWeekList {
 			NewMatch("33", "32"),
 			NewMatch("56", "51"),
 			NewMatch("46", "41")),
-		NewWeek("27 Jan", 6, 9, false,
+		NewWeek("27 Jan", 18, 21, false,
 			NewMatch("25", "22"),
 			NewMatch("23", "24"),
 			NewMatch("13", "12"),
@@ -419,7 +512,7 @@ func BuildFixtureList() FixtureWeekList {
 			NewMatch("47", "45"),
 			NewMatch("37", "35"),
 			NewMatch("54", "58")),
-		NewWeek("3 Feb", 6, 9, false,
+		NewWeek("3 Feb", 18, 21, false,
 			NewMatch("11", "16"),
 			NewMatch("44", "48"),
 			NewMatch("34", "38"),
@@ -428,7 +521,7 @@ func BuildFixtureList() FixtureWeekList {
 			NewMatch("39", "33"),
 			NewMatch("52", "510"),
 			NewMatch("42", "410")),
-		NewWeek("10 Feb", 5, 9, false,
+		NewWeek("10 Feb", 17, 21, false,
 			NewMatch("32", "310"),
 			NewMatch("51", "55"),
 			NewMatch("26", "22"),
@@ -439,7 +532,7 @@ func BuildFixtureList() FixtureWeekList {
 			NewMatch("31", "35"),
 			NewMatch("56", "54"),
 			NewMatch("46", "44")),
-		NewWeek("17 Feb", 6, 9, false,
+		NewWeek("17 Feb", 18, 21, false,
 			NewMatch("25", "24"),
 			NewMatch("36", "34"),
 			NewMatch("53", "57"),
@@ -448,7 +541,7 @@ func BuildFixtureList() FixtureWeekList {
 			NewMatch("58", "52"),
 			NewMatch("48", "42"),
 			NewMatch("38", "32")),
-		NewWeek("24 Feb", 6, 9, false,
+		NewWeek("24 Feb", 18, 21, false,
 			NewMatch("23", "26"),
 			NewMatch("16", "12"),
 			NewMatch("11", "13"),
@@ -457,7 +550,7 @@ func BuildFixtureList() FixtureWeekList {
 			NewMatch("310", "39"),
 			NewMatch("54", "51"),
 			NewMatch("44", "41")),
-		NewWeek("3 Mar", 6, 9, false,
+		NewWeek("3 Mar", 18, 21, false,
 			NewMatch("22", "24"),
 			NewMatch("15", "14"),
 			NewMatch("34", "31"),
@@ -466,7 +559,7 @@ func BuildFixtureList() FixtureWeekList {
 			NewMatch("52", "56"),
 			NewMatch("57", "510"),
 			NewMatch("59", "58")),
-		NewWeek("10 Mar", 6, 9, false,
+		NewWeek("10 Mar", 18, 21, false,
 			NewMatch("25", "21"),
 			NewMatch("13", "16"),
 			NewMatch("35", "33"),
@@ -475,7 +568,7 @@ func BuildFixtureList() FixtureWeekList {
 			NewMatch("47", "410"),
 			NewMatch("37", "310"),
 			NewMatch("49", "48")),
-		NewWeek("17 Mar", 6, 7, false,
+		NewWeek("17 Mar", 18, 19, false,
 			NewMatch("12", "14"),
 			NewMatch("15", "11"),
 			NewMatch("39", "38")),
@@ -484,7 +577,7 @@ func BuildFixtureList() FixtureWeekList {
 		//NewMatch("510", "55"),
 		//NewMatch("56", "59"),
 		//NewMatch("58", "57")),
-		//NewWeek("24 Mar", 6, 9, false,
+		//NewWeek("24 Mar", 18, 21, false,
 		//	NewMatch("26", "24"),
 		//	NewMatch("23", "25"),
 		//	NewMatch("21", "22"),
@@ -493,7 +586,7 @@ func BuildFixtureList() FixtureWeekList {
 		//	NewMatch("410", "45"),
 		//	NewMatch("46", "49"),
 		//	NewMatch("48", "47")),
-		//NewWeek("31 Mar", 6, 9, false,
+		//NewWeek("31 Mar", 18, 21, false,
 		//	NewMatch("16", "14"),
 		//	NewMatch("13", "15"),
 		//	NewMatch("11", "12"),
@@ -503,12 +596,22 @@ func BuildFixtureList() FixtureWeekList {
 		//	NewMatch("36", "39"),
 		//	NewMatch("38", "37")),
 	}
-	return weeks
+	answer, err := weeks.InSeason(Season{Year: 2018, TimeZone: "Europe/London"})
+	if err != nil {
+		return nil, err
+	}
+	return answer.WithDivisions([]Division{
+		{"1", []string{"11", "12", "13", "14", "15", "16"}},
+		{"2", []string{"21", "22", "23", "24", "25", "26"}},
+		{"3", []string{"31", "32", "33", "34", "35", "36", "37", "38", "39", "310"}},
+		{"4", []string{"41", "42", "43", "44", "45", "46", "47", "48", "49", "410"}},
+		{"5", []string{"51", "52", "53", "54", "55", "56", "57", "58", "59", "510"}},
+	}), nil
 }
 
 func combinations(itemCount int) int {
-	if itemCount == 2 {
-		return 2
+	if itemCount <= 1 {
+		return 1
 	}
 	return itemCount * combinations(itemCount-1)
 }
@@ -1,15 +1,25 @@
 package fixtures
 
 import (
-	"github.com/stretchr/testify/assert"
-	"testing"
 	"bytes"
+	"github.com/stretchr/testify/assert"
 	"regexp"
 	"strconv"
+	"testing"
 )
 
+// buildFixtureList returns the fixture list built by BuildFixtureList,
+// failing the test if it cannot be built.
+func buildFixtureList(t *testing.T) FixtureWeekList {
+	answer, err := BuildFixtureList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return answer
+}
+
 func TestBuildFixtureList(t *testing.T) {
-	fl := BuildFixtureList()
+	fl := buildFixtureList(t)
 	assert.Equal(t, 22, len(fl))
 	for _, w := range fl {
 		assert.Equal(t, len(w.timeslots), len(w.matches))
@@ -25,36 +35,36 @@ func TestCombinations(t *testing.T) {
 }
 
 func TestWeek_Combination_0(t *testing.T) {
-	week := BuildFixtureList()[0]
+	week := buildFixtureList(t)[0]
 	fixtures := week.combination(0)
 	assert.Equal(t, 6, len(fixtures))
 	index := Incrementable(0)
-	checkExpected(t, "25", "26", "30 Sep", 6, "A", fixtures[index.postInc()])
-	checkExpected(t, "21", "24", "30 Sep", 6, "B", fixtures[index.postInc()])
-	checkExpected(t, "23", "22", "30 Sep", 7, "A", fixtures[index.postInc()])
-	checkExpected(t, "15", "16", "30 Sep", 7, "B", fixtures[index.postInc()])
-	checkExpected(t, "51", "52", "30 Sep", 8, "A", fixtures[index.postInc()])
-	checkExpected(t, "41", "42", "30 Sep", 8, "B", fixtures[index.postInc()])
+	checkExpected(t, "25", "26", "30 Sep", 18, "A", fixtures[index.postInc()])
+	checkExpected(t, "21", "24", "30 Sep", 18, "B", fixtures[index.postInc()])
+	checkExpected(t, "23", "22", "30 Sep", 19, "A", fixtures[index.postInc()])
+	checkExpected(t, "15", "16", "30 Sep", 19, "B", fixtures[index.postInc()])
+	checkExpected(t, "51", "52", "30 Sep", 20, "A", fixtures[index.postInc()])
+	checkExpected(t, "41", "42", "30 Sep", 20, "B", fixtures[index.postInc()])
 }
 
 func TestWeek_Combination_500(t *testing.T) {
-	week := BuildFixtureList()[0]
+	week := buildFixtureList(t)[0]
 	fixtures := week.combination(500)
 	assert.Equal(t, 6, len(fixtures))
 	index := Incrementable(0)
-	checkExpected(t, "23", "22", "30 Sep", 6, "A", fixtures[index.postInc()])
-	checkExpected(t, "51", "52", "30 Sep", 6, "B", fixtures[index.postInc()])
-	checkExpected(t, "25", "26", "30 Sep", 7, "A", fixtures[index.postInc()])
-	checkExpected(t, "15", "16", "30 Sep", 7, "B", fixtures[index.postInc()])
-	checkExpected(t, "41", "42", "30 Sep", 8, "A", fixtures[index.postInc()])
-	checkExpected(t, "21", "24", "30 Sep", 8, "B", fixtures[index.postInc()])
+	checkExpected(t, "23", "22", "30 Sep", 18, "A", fixtures[index.postInc()])
+	checkExpected(t, "51", "52", "30 Sep", 18, "B", fixtures[index.postInc()])
+	checkExpected(t, "25", "26", "30 Sep", 19, "A", fixtures[index.postInc()])
+	checkExpected(t, "15", "16", "30 Sep", 19, "B", fixtures[index.postInc()])
+	checkExpected(t, "41", "42", "30 Sep", 20, "A", fixtures[index.postInc()])
+	checkExpected(t, "21", "24", "30 Sep", 20, "B", fixtures[index.postInc()])
 }
 
 func checkExpected(t *testing.T, team1 string, team2 string, date string, timeslot int, court string, sm *ScheduledMatch) {
 	assert.Equal(t, team1, sm.team1)
 	assert.Equal(t, team2, sm.team2)
 	assert.Equal(t, date, sm.date)
-	assert.Equal(t, timeslot, sm.timeslot)
+	assert.Equal(t, At(timeslot, 15), sm.timeslot)
 	assert.Equal(t, court, sm.court)
 }
 
@@ -84,7 +94,7 @@ func TestIncrementable(t *testing.T) {
 }
 
 func TestInitialIterator(t *testing.T) {
-	list := BuildFixtureList()
+	list := buildFixtureList(t)
 	it := list.Iterator()
 	assert.Equal(t, len(list), len(it.nextIndices))
 	for _, v := range it.nextIndices {
@@ -93,7 +103,7 @@ func TestInitialIterator(t *testing.T) {
 }
 
 func TestIteratorWithStartPosition(t *testing.T) {
-	list := BuildFixtureList()
+	list := buildFixtureList(t)
 	it := list.Iterator(1, 2, 3, 4)
 	assert.Equal(t, len(list), len(it.nextIndices))
 	for i, v := range it.nextIndices {
@@ -155,7 +165,6 @@ func TestIteratorNextFirstIteration(t *testing.T) {
 	checkExpected(t, "35", "36", "2 Jun", 6, "B", matches[index.postInc()])
 }
 
-
 func TestIteratorAllIterations(t *testing.T) {
 	weeks := FixtureWeekList{
 		NewWeek("31 May", 1, 2, true,
@@ -224,7 +233,7 @@ func TestTeamSchedules(t *testing.T) {
 }
 
 func TestTeamScheduleEvaluate(t *testing.T) {
-	list := BuildFixtureList()
+	list := buildFixtureList(t)
 	fl, ok := list.Iterator().Next()
 	assert.True(t, ok)
 	checkItem := func(ts *TeamSchedule, length int, score int) {
@@ -236,7 +245,7 @@ func TestTeamScheduleEvaluate(t *testing.T) {
 }
 
 func TestScheduleEvaluate(t *testing.T) {
-	list := BuildFixtureList()
+	list := buildFixtureList(t)
 	fl, ok := list.Iterator().Next()
 	assert.True(t, ok)
 	assert.Equal(t, 164, fl.Evaluate())
@@ -252,4 +261,49 @@ func TestRegexp(t *testing.T) {
 		islice[i], _ = strconv.Atoi(v)
 	}
 	assert.Equal(t, []int{143, 114, 2, 8}, islice)
-}
\ No newline at end of file
+}
+func TestNewWeekWithCourts(t *testing.T) {
+	matches := []*Match{NewMatch("11", "12"), NewMatch("13", "14"), NewMatch("15", "16"),
+		NewMatch("17", "18"), NewMatch("19", "20"), NewMatch("21", "22")}
+	w := NewWeekWithCourts("31 May", 6, 9, true, []string{"Hall 1", "Hall 2", "Hall 3"}, matches...)
+	assert.Equal(t, quarterPast(6, 7, 7, 7, 8, 8), w.timeslots)
+	courts := make([]string, len(w.timeslots))
+	for i := range courts {
+		courts[i] = w.court(i)
+	}
+	assert.Equal(t, []string{"Hall 1", "Hall 1", "Hall 2", "Hall 3", "Hall 1", "Hall 2"}, courts)
+	w = NewWeekWithCourts("31 May", 6, 9, false, []string{"A"}, matches[:3]...)
+	assert.Equal(t, quarterPast(6, 7, 8), w.timeslots)
+	assert.Equal(t, "A", w.court(2))
+	assert.Equal(t, NewWeekWithCourts("31 May", 6, 9, false, DefaultCourts, matches...), NewWeek("31 May", 6, 9, false, matches...))
+}
+
+func TestFixtureWeekListCourts(t *testing.T) {
+	list := FixtureWeekList{
+		NewWeekWithCourts("31 May", 6, 9, false, []string{"A", "B", "C"}, NewMatch("11", "12")),
+		NewWeekWithCourts("1 Jun", 6, 9, false, []string{"B", "D"}, NewMatch("11", "12")),
+	}
+	assert.Equal(t, []string{"A", "B", "C", "D"}, list.Courts())
+	assert.False(t, list.sameCourts())
+	assert.True(t, buildFixtureList(t).sameCourts())
+}
+
+func TestSizeAndRank(t *testing.T) {
+	list := FixtureWeekList{
+		NewWeek("31 May", 6, 6, false, NewMatch("11", "12"), NewMatch("13", "14")),
+		NewWeek("7 Jun", 6, 7, false, NewMatch("11", "13"), NewMatch("12", "14"), NewMatch("15", "16")),
+	}
+	first, second := int64(list[0].combinationCount), int64(list[1].combinationCount)
+	assert.Equal(t, first*second, list.Size().Int64())
+	assert.Zero(t, list.Rank().Int64())
+	assert.Equal(t, second+2, list.Rank(1, 2).Int64())
+	assert.Equal(t, second, list.Rank(1).Int64())
+	it := list.Iterator()
+	count := int64(0)
+	for _, ok := it.Next(); ok; _, ok = it.Next() {
+		count++
+		assert.Equal(t, count, list.Rank(it.NextIndices()...).Int64())
+	}
+	assert.Equal(t, list.Size(), list.Rank(it.NextIndices()...))
+	assert.Zero(t, FixtureWeekList{}.Size().Int64())
+}
@@ -0,0 +1,193 @@
+package fixtures
+
+import (
+	"fmt"
+)
+
+type Enumeration int
+
+const (
+	Permutations Enumeration = iota
+	Pairings
+	PairingsIgnoringCourts
+)
+
+var enumerationNames = map[Enumeration]string{
+	Permutations:           "permutations",
+	Pairings:               "pairings",
+	PairingsIgnoringCourts: "pairings-ignoring-courts",
+}
+
+func (e Enumeration) String() string {
+	return enumerationNames[e]
+}
+
+func ParseEnumeration(name string) (Enumeration, error) {
+	for e, n := range enumerationNames {
+		if n == name {
+			return e, nil
+		}
+	}
+	return Permutations, fmt.Errorf("unknown enumeration %q", name)
+}
+
+// CheckScoring returns an error if the enumeration can miss the best
+// schedule under the scoring configuration given. PairingsIgnoringCourts
+// keeps the courts in the order of the timeslots, so it only finds the
+// best schedule when no criterion weighs the courts.
+func (e Enumeration) CheckScoring(config ScoringConfig) error {
+	if e != PairingsIgnoringCourts {
+		return nil
+	}
+	for _, c := range config.Criteria {
+		if c.Attribute == "court" && c.Weight != 0 {
+			return fmt.Errorf("enumeration %v cannot find the best schedule when the court criterion has weight %d: give it weight 0 or enumerate %v", e, c.Weight, Pairings)
+		}
+	}
+	return nil
+}
+
+// Relabelling the courts throughout the season turns each schedule into
+// its mirror image, with every team's court balance unchanged. That only
+// holds when every timeslot fills every court of the same set: a lone
+// match, such as that of a firstTimeSingle timeslot, is always on the first
+// court and has no mirror image. When it holds, Pairings fixes the courts of
+// the first week's first timeslot, so that it visits one schedule of each
+// mirror-image set rather than all of them. That halves the search space at
+// most, and leaves it whole otherwise.
+func (fl FixtureWeekList) WithEnumeration(e Enumeration) FixtureWeekList {
+	answer := make(FixtureWeekList, len(fl))
+	interchangeable := fl.courtsInterchangeable()
+	for i, w := range fl {
+		week := *w
+		week.enumeration = e
+		week.fixedCourts = e == Pairings && i == 0 && interchangeable
+		week.combinationCount = week.countCombinations()
+		answer[i] = &week
+	}
+	return answer
+}
+
+// courtsInterchangeable reports whether relabelling the courts maps every
+// schedule of the fixture list onto another.
+func (fl FixtureWeekList) courtsInterchangeable() bool {
+	if !fl.sameCourts() {
+		return false
+	}
+	for _, w := range fl {
+		for _, size := range w.groupSizes() {
+			if size != len(w.courts) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (w *Week) countCombinations() int {
+	if w.enumeration == Permutations {
+		return combinations(len(w.matches))
+	}
+	answer := 1
+	remaining := len(w.matches)
+	for i, size := range w.groupSizes() {
+		answer *= choose(remaining, size)
+		if w.orientsCourts(i) {
+			answer *= combinations(size)
+		}
+		remaining -= size
+	}
+	return answer
+}
+
+func (w *Week) pairingCombination(comb int) Schedule {
+	answer := make(Schedule, 0, len(w.matches))
+	remainingMatches := w.matches
+	c := comb
+	for i, size := range w.groupSizes() {
+		var subset, orientation int
+		c, subset = divmod(c, choose(len(remainingMatches), size))
+		chosen := unrankSubset(subset, len(remainingMatches), size)
+		group := make([]*Match, size)
+		for j, mi := range chosen {
+			group[j] = remainingMatches[mi]
+		}
+		if w.orientsCourts(i) {
+			c, orientation = divmod(c, combinations(size))
+		}
+		for _, m := range permutation(group, orientation) {
+			position := len(answer)
+			answer = append(answer, w.schedule(m, w.timeslots[position], w.court(position)))
+		}
+		remainingMatches = copyWithoutItems(remainingMatches, chosen)
+	}
+	return answer
+}
+
+func (w *Week) orientsCourts(group int) bool {
+	return w.enumeration == Pairings && !(w.fixedCourts && group == 0)
+}
+
+func (w *Week) groupSizes() []int {
+	answer := make([]int, 0, len(w.timeslots))
+	for i := range w.timeslots {
+		if i < len(w.matches) {
+			if i == 0 || w.timeslots[i] != w.timeslots[i-1] {
+				answer = append(answer, 0)
+			}
+			answer[len(answer)-1]++
+		}
+	}
+	return answer
+}
+
+func permutation(items []*Match, index int) []*Match {
+	answer := make([]*Match, 0, len(items))
+	remaining := items
+	c := index
+	for range items {
+		var i int
+		c, i = divmod(c, len(remaining))
+		answer = append(answer, remaining[i])
+		remaining = copyWithoutItemAt(remaining, i)
+	}
+	return answer
+}
+
+func unrankSubset(rank int, itemCount int, size int) []int {
+	answer := make([]int, 0, size)
+	r := rank
+	for i := 0; len(answer) < size; i++ {
+		withI := choose(itemCount-i-1, size-len(answer)-1)
+		if r < withI {
+			answer = append(answer, i)
+		} else {
+			r -= withI
+		}
+	}
+	return answer
+}
+
+func copyWithoutItems(list []*Match, indices []int) []*Match {
+	answer := make([]*Match, 0, len(list)-len(indices))
+	next := 0
+	for i, m := range list {
+		if next < len(indices) && indices[next] == i {
+			next++
+		} else {
+			answer = append(answer, m)
+		}
+	}
+	return answer
+}
+
+func choose(n int, k int) int {
+	if k < 0 || k > n {
+		return 0
+	}
+	answer := 1
+	for i := 1; i <= k; i++ {
+		answer = answer * (n - k + i) / i
+	}
+	return answer
+}
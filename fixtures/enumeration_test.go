@@ -0,0 +1,217 @@
+package fixtures
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestParseEnumeration(t *testing.T) {
+	for _, e := range []Enumeration{Permutations, Pairings, PairingsIgnoringCourts} {
+		parsed, err := ParseEnumeration(e.String())
+		assert.Nil(t, err)
+		assert.Equal(t, e, parsed)
+	}
+	_, err := ParseEnumeration("rubbish")
+	assert.NotNil(t, err)
+}
+
+func TestChoose(t *testing.T) {
+	assert.Equal(t, 28, choose(8, 2))
+	assert.Equal(t, 1, choose(2, 2))
+	assert.Equal(t, 5, choose(5, 1))
+	assert.Equal(t, 0, choose(1, 2))
+}
+
+func TestUnrankSubset(t *testing.T) {
+	subsets := make([][]int, choose(4, 2))
+	for i := range subsets {
+		subsets[i] = unrankSubset(i, 4, 2)
+	}
+	assert.Equal(t, [][]int{{0, 1}, {0, 2}, {0, 3}, {1, 2}, {1, 3}, {2, 3}}, subsets)
+}
+
+func TestEnumerationCombinationCounts(t *testing.T) {
+	list := buildFixtureList(t)
+	pairings := list.WithEnumeration(Pairings)
+	ignoringCourts := list.WithEnumeration(PairingsIgnoringCourts)
+	assert.Equal(t, 720, list[0].combinationCount)
+	assert.Equal(t, 90, ignoringCourts[0].combinationCount)
+	assert.Equal(t, 40320, list[1].combinationCount)
+	assert.Equal(t, 2520, ignoringCourts[1].combinationCount)
+	assert.Equal(t, 362880, list[8].combinationCount)
+	assert.Equal(t, 22680, ignoringCourts[8].combinationCount)
+	assert.Equal(t, Permutations, list[0].enumeration)
+	// The season's lone first matches have no mirror image, so pairings
+	// visit every schedule that permutations do.
+	assert.False(t, list.courtsInterchangeable())
+	assert.Equal(t, list.Size(), pairings.Size())
+	for i, w := range list {
+		assert.Equal(t, w.combinationCount, pairings[i].combinationCount)
+	}
+}
+
+func TestPairingsHalveTheSpaceWhenCourtsAreInterchangeable(t *testing.T) {
+	matches := []*Match{NewMatch("11", "12"), NewMatch("13", "14"), NewMatch("15", "16"), NewMatch("17", "18")}
+	list := FixtureWeekList{
+		NewWeekWithCourts("31 May", 6, 7, false, []string{"A", "B"}, matches...),
+		NewWeekWithCourts("1 Jun", 6, 7, false, []string{"A", "B"}, matches...),
+	}
+	half := new(big.Int).Div(list.Size(), big.NewInt(2))
+	assert.Equal(t, half, list.WithEnumeration(Pairings).Size())
+	assert.Equal(t, big.NewInt(576), list.Size())
+	assert.Equal(t, big.NewInt(36), list.WithEnumeration(PairingsIgnoringCourts).Size())
+}
+
+func TestCheckScoring(t *testing.T) {
+	config := DefaultScoringConfig()
+	assert.Nil(t, Permutations.CheckScoring(config))
+	assert.Nil(t, Pairings.CheckScoring(config))
+	assert.NotNil(t, PairingsIgnoringCourts.CheckScoring(config))
+	for i, c := range config.Criteria {
+		if c.Attribute == "court" {
+			config.Criteria[i].Weight = 0
+		}
+	}
+	assert.Nil(t, PairingsIgnoringCourts.CheckScoring(config))
+}
+
+func TestPairingsCoverEveryPermutation(t *testing.T) {
+	week := buildFixtureList(t)[1]
+	permutations := weekArrangements(week, false)
+	assert.Equal(t, 40320, len(permutations))
+	pairings := weekArrangements(FixtureWeekList{week, week}.WithEnumeration(Pairings)[1], false)
+	assert.Equal(t, permutations, pairings)
+	ignoringCourts := weekArrangements(FixtureWeekList{week}.WithEnumeration(PairingsIgnoringCourts)[0], true)
+	assert.Equal(t, 2520, len(ignoringCourts))
+	assert.Equal(t, weekArrangements(week, true), ignoringCourts)
+}
+
+func TestPairingsFindTheOptimum(t *testing.T) {
+	best := func(list FixtureWeekList) int {
+		answer := -1
+		it := list.Iterator()
+		for s, ok := it.Next(); ok; s, ok = it.Next() {
+			if score := s.Evaluate(); answer == -1 || score < answer {
+				answer = score
+			}
+		}
+		return answer
+	}
+	list := FixtureWeekList{
+		NewWeek("31 May", 1, 2, false,
+			NewMatch("11", "12"),
+			NewMatch("13", "14"),
+			NewMatch("15", "16"),
+			NewMatch("17", "18")),
+		NewWeek("1 Jun", 1, 2, false,
+			NewMatch("11", "13"),
+			NewMatch("12", "15"),
+			NewMatch("14", "17"),
+			NewMatch("16", "18")),
+		NewWeek("2 Jun", 1, 2, false,
+			NewMatch("11", "14"),
+			NewMatch("12", "16"),
+			NewMatch("13", "18"),
+			NewMatch("15", "17")),
+	}
+	pairings := list.WithEnumeration(Pairings)
+	assert.Equal(t, 12, pairings[0].combinationCount)
+	assert.Equal(t, 24, pairings[1].combinationCount)
+	assert.Equal(t, best(list), best(pairings))
+}
+
+func weekArrangements(w *Week, ignoreCourts bool) []string {
+	answer := make(map[string]bool)
+	for c := 0; c < w.combinationCount; c++ {
+		lines := make([]string, 0, len(w.matches))
+		for _, m := range w.combination(c) {
+			if ignoreCourts {
+				m.court = ""
+			}
+			lines = append(lines, m.String())
+		}
+		sort.Strings(lines)
+		answer[strings.Join(lines, "")] = true
+	}
+	keys := make([]string, 0, len(answer))
+	for k := range answer {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestPairingsFixCourtsOnlyWhenEveryWeekHasTheSameCourts(t *testing.T) {
+	matches := []*Match{NewMatch("11", "12"), NewMatch("13", "14"), NewMatch("15", "16"), NewMatch("17", "18")}
+	same := FixtureWeekList{
+		NewWeekWithCourts("31 May", 6, 7, false, []string{"A", "B"}, matches...),
+		NewWeekWithCourts("1 Jun", 6, 7, false, []string{"A", "B"}, matches...),
+	}.WithEnumeration(Pairings)
+	assert.True(t, same[0].fixedCourts)
+	different := FixtureWeekList{
+		NewWeekWithCourts("31 May", 6, 7, false, []string{"A", "B"}, matches...),
+		NewWeekWithCourts("1 Jun", 6, 9, false, []string{"A"}, matches...),
+	}.WithEnumeration(Pairings)
+	assert.False(t, different[0].fixedCourts)
+	assert.Equal(t, 2*same[0].combinationCount, different[0].combinationCount)
+}
+
+func TestPairingsFindTheOptimumWithSingleTimeslots(t *testing.T) {
+	best := func(list FixtureWeekList, scorer Scorer) Score {
+		var answer Score
+		it := list.Iterator()
+		for s, ok := it.Next(); ok; s, ok = it.Next() {
+			if score := s.ScoreWith(scorer); score.Better(answer) {
+				answer = score
+			}
+		}
+		return answer
+	}
+	list := FixtureWeekList{
+		NewWeek("31 May", 6, 7, true, NewMatch("11", "12"), NewMatch("13", "14"), NewMatch("15", "16")),
+		NewWeek("1 Jun", 6, 7, true, NewMatch("11", "13"), NewMatch("12", "15"), NewMatch("14", "16")),
+		NewWeek("2 Jun", 6, 7, false, NewMatch("11", "14"), NewMatch("12", "16"), NewMatch("13", "15")),
+	}
+	courtsOnly, err := NewScorer(ScoringConfig{Criteria: []Criterion{{Attribute: "court", Weight: 1, Values: []string{"A", "B"}}}})
+	assert.Nil(t, err)
+	pairings := list.WithEnumeration(Pairings)
+	for _, scorer := range []Scorer{DefaultScorer, courtsOnly} {
+		assert.Equal(t, best(list, scorer), best(pairings, scorer))
+	}
+}
+
+func TestPairingsSkipMirrorImages(t *testing.T) {
+	list := FixtureWeekList{
+		NewWeek("31 May", 6, 7, false, NewMatch("11", "12"), NewMatch("13", "14"), NewMatch("15", "16"), NewMatch("17", "18")),
+		NewWeek("1 Jun", 6, 7, false, NewMatch("11", "13"), NewMatch("12", "15"), NewMatch("14", "17"), NewMatch("16", "18")),
+	}
+	mirror := strings.NewReplacer(", A:", ", B:", ", B:", ", A:")
+	images := func(list FixtureWeekList) (int, map[string]bool) {
+		count := 0
+		answer := make(map[string]bool)
+		it := list.Iterator()
+		for s, ok := it.Next(); ok; s, ok = it.Next() {
+			count++
+			text, other := sortedLines(s.String()), sortedLines(mirror.Replace(s.String()))
+			if other < text {
+				text = other
+			}
+			answer[text] = true
+		}
+		return count, answer
+	}
+	count, all := images(list)
+	pairingsCount, pairings := images(list.WithEnumeration(Pairings))
+	assert.Equal(t, count/2, pairingsCount)
+	assert.Equal(t, pairingsCount, len(pairings))
+	assert.Equal(t, all, pairings)
+}
+
+func sortedLines(text string) string {
+	lines := strings.SplitAfter(text, "\n")
+	sort.Strings(lines)
+	return strings.Join(lines, "")
+}
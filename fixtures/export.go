@@ -0,0 +1,192 @@
+package fixtures
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+type ScheduleFormat int
+
+const (
+	TextFormat ScheduleFormat = iota
+	CSVFormat
+	JSONFormat
+)
+
+var scheduleFormatNames = map[ScheduleFormat]string{
+	TextFormat: "text",
+	CSVFormat:  "csv",
+	JSONFormat: "json",
+}
+
+func (f ScheduleFormat) String() string {
+	return scheduleFormatNames[f]
+}
+
+func ParseScheduleFormat(name string) (ScheduleFormat, error) {
+	for f, n := range scheduleFormatNames {
+		if n == name {
+			return f, nil
+		}
+	}
+	return TextFormat, fmt.Errorf("unknown schedule format %q", name)
+}
+
+// scheduleFormatOf returns the format of a schedule file from its
+// extension, .csv or .json, or TextFormat for any other.
+func scheduleFormatOf(filename string) ScheduleFormat {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return CSVFormat
+	case ".json":
+		return JSONFormat
+	}
+	return TextFormat
+}
+
+// exportedMatch is a scheduled match as it is exported. The date is in ISO
+// 8601 form if the match's week is in the calendar, and the division is
+// that of the home team, or empty if the season does not give it.
+type exportedMatch struct {
+	Date     string `json:"date"`
+	Time     Time   `json:"time"`
+	Court    string `json:"court"`
+	Home     string `json:"home"`
+	Away     string `json:"away"`
+	Division string `json:"division"`
+}
+
+var exportedColumns = []string{"date", "time", "court", "home", "away", "division"}
+
+func exportMatch(m *ScheduledMatch) exportedMatch {
+	answer := exportedMatch{
+		Date:     m.date,
+		Time:     m.timeslot,
+		Court:    m.court,
+		Home:     m.team1,
+		Away:     m.team2,
+		Division: m.division,
+	}
+	if !m.day.IsZero() {
+		answer.Date = m.day.Format(isoDateFormat)
+	}
+	return answer
+}
+
+func (e *exportedMatch) scheduledMatch() (*ScheduledMatch, error) {
+	date, err := normaliseDate(e.Date)
+	if err != nil {
+		return nil, err
+	}
+	if e.Home == "" || e.Away == "" {
+		return nil, fmt.Errorf("match %q v %q has a missing team", e.Home, e.Away)
+	}
+	return NewScheduledMatch(NewMatch(e.Home, e.Away), date, e.Time, e.Court), nil
+}
+
+// WriteSchedule writes the schedule in the format given: as Schedule.String
+// writes it, or with a row or object for each match giving its date, time,
+// court, home and away teams and division.
+func WriteSchedule(w io.Writer, s Schedule, format ScheduleFormat) error {
+	switch format {
+	case CSVFormat:
+		return WriteScheduleCSV(w, s)
+	case JSONFormat:
+		return WriteScheduleJSON(w, s)
+	}
+	_, err := io.WriteString(w, s.String())
+	return err
+}
+
+func WriteScheduleCSV(w io.Writer, s Schedule) error {
+	cw := csv.NewWriter(w)
+	cw.Write(exportedColumns)
+	for _, m := range s {
+		e := exportMatch(m)
+		cw.Write([]string{e.Date, e.Time.String(), e.Court, e.Home, e.Away, e.Division})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func WriteScheduleJSON(w io.Writer, s Schedule) error {
+	matches := make([]exportedMatch, len(s))
+	for i, m := range s {
+		matches[i] = exportMatch(m)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(matches)
+}
+
+// LoadScheduleCSV reads a schedule written by WriteScheduleCSV. The
+// columns may be in any order, and the division column may be left out.
+func LoadScheduleCSV(r io.Reader) (Schedule, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("no header found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range exportedColumns[:5] {
+		if _, found := columns[name]; !found {
+			return nil, fmt.Errorf("no %s column found", name)
+		}
+	}
+	answer := make(Schedule, 0)
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		field := func(name string) string {
+			if i, found := columns[name]; found && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		e := exportedMatch{Date: field("date"), Court: field("court"), Home: field("home"), Away: field("away")}
+		if e.Time, err = ParseTime(field("time")); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		m, err := e.scheduledMatch()
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		answer = append(answer, m)
+	}
+	return answer, nil
+}
+
+// LoadScheduleJSON reads a schedule written by WriteScheduleJSON.
+func LoadScheduleJSON(r io.Reader) (Schedule, error) {
+	var matches []exportedMatch
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&matches); err != nil {
+		return nil, err
+	}
+	answer := make(Schedule, len(matches))
+	for i := range matches {
+		m, err := matches[i].scheduledMatch()
+		if err != nil {
+			return nil, fmt.Errorf("match %d: %v", i+1, err)
+		}
+		answer[i] = m
+	}
+	return answer, nil
+}
@@ -0,0 +1,106 @@
+package fixtures
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteScheduleCSV(t *testing.T) {
+	s := icsTestSchedule(t)
+	var buffer bytes.Buffer
+	assert.Nil(t, WriteSchedule(&buffer, s, CSVFormat))
+	lines := strings.Split(buffer.String(), "\n")
+	assert.Equal(t, "date,time,court,home,away,division", lines[0])
+	assert.Equal(t, "2018-09-30,18.15,A,25,26,2", lines[1])
+	assert.Equal(t, len(s)+2, len(lines))
+	loaded, err := LoadScheduleCSV(&buffer)
+	assert.Nil(t, err)
+	assert.Equal(t, s.String(), loaded.String())
+	assert.Equal(t, s.Score(), loaded.Score())
+}
+
+func TestWriteScheduleJSON(t *testing.T) {
+	s := icsTestSchedule(t)
+	var buffer bytes.Buffer
+	assert.Nil(t, WriteSchedule(&buffer, s[:1], JSONFormat))
+	assert.Equal(t, `[
+  {
+    "date": "2018-09-30",
+    "time": "18.15",
+    "court": "A",
+    "home": "25",
+    "away": "26",
+    "division": "2"
+  }
+]
+`, buffer.String())
+	buffer.Reset()
+	assert.Nil(t, WriteSchedule(&buffer, s, JSONFormat))
+	loaded, err := LoadScheduleJSON(&buffer)
+	assert.Nil(t, err)
+	assert.Equal(t, s.String(), loaded.String())
+}
+
+func TestExportedDivision(t *testing.T) {
+	list := FixtureWeekList{NewWeek("31 May", 18, 18, false, NewMatch("11", "12"), NewMatch("x1", "x2"))}
+	var buffer bytes.Buffer
+	assert.Nil(t, WriteScheduleCSV(&buffer, list.Combination(0, 0)))
+	assert.Equal(t, "date,time,court,home,away,division\n31 May,18.15,A,11,12,\n31 May,18.15,B,x1,x2,\n", buffer.String())
+	buffer.Reset()
+	list = list.WithDivisions([]Division{{Name: "Premier", Teams: []string{"11", "12"}}})
+	assert.Nil(t, WriteScheduleCSV(&buffer, list.Combination(0, 0)))
+	assert.Equal(t, "date,time,court,home,away,division\n31 May,18.15,A,11,12,Premier\n31 May,18.15,B,x1,x2,\n", buffer.String())
+}
+
+func TestLoadScheduleCSVColumns(t *testing.T) {
+	s, err := LoadScheduleCSV(strings.NewReader("Home,Away,Date,Time,Court\n11,12,31 May,6.15,Hall 1\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, "31 May, 6.15, Hall 1: 11 v 12\n", s.String())
+}
+
+func TestLoadExportedScheduleNotValid(t *testing.T) {
+	checker := func(load func(r *strings.Reader) (Schedule, error), data string, message string) {
+		s, err := load(strings.NewReader(data))
+		assert.Nil(t, s)
+		assert.EqualError(t, err, message)
+	}
+	csvLoader := func(r *strings.Reader) (Schedule, error) { return LoadScheduleCSV(r) }
+	jsonLoader := func(r *strings.Reader) (Schedule, error) { return LoadScheduleJSON(r) }
+	checker(csvLoader, "", "no header found")
+	checker(csvLoader, "date,time,home,away\n", "no court column found")
+	checker(csvLoader, "date,time,court,home,away\n31 May,six,A,11,12\n", "line 2: time \"six\" is not in the form 6.15")
+	checker(csvLoader, "date,time,court,home,away\n31/5,6.15,A,11,12\n", "line 2: date \"31/5\" is not in the form 30 Sep")
+	checker(csvLoader, "date,time,court,home,away\n31 May,6.15,A,11\n", "line 2: match \"11\" v \"\" has a missing team")
+	checker(jsonLoader, `[{"date": "31 May", "time": "18.15", "court": "A", "home": "11", "away": "12", "venue": "x"}]`,
+		"json: unknown field \"venue\"")
+	checker(jsonLoader, `[{"date": "31 May", "time": "18.15", "court": "A", "home": "11"}]`,
+		"match 1: match \"11\" v \"\" has a missing team")
+}
+
+func TestReadScheduleByExtension(t *testing.T) {
+	dir := t.TempDir()
+	s := icsTestSchedule(t)
+	for _, format := range []ScheduleFormat{TextFormat, CSVFormat, JSONFormat} {
+		filename := filepath.Join(dir, "best."+format.String())
+		var buffer bytes.Buffer
+		assert.Nil(t, WriteSchedule(&buffer, s, format))
+		assert.Nil(t, os.WriteFile(filename, buffer.Bytes(), 0644))
+		loaded, err := ReadSchedule(filename)
+		assert.Nil(t, err, format.String())
+		assert.Equal(t, s.String(), loaded.String())
+	}
+}
+
+func TestParseScheduleFormat(t *testing.T) {
+	for _, f := range []ScheduleFormat{TextFormat, CSVFormat, JSONFormat} {
+		parsed, err := ParseScheduleFormat(f.String())
+		assert.Nil(t, err)
+		assert.Equal(t, f, parsed)
+	}
+	_, err := ParseScheduleFormat("xml")
+	assert.EqualError(t, err, "unknown schedule format \"xml\"")
+}
@@ -0,0 +1,154 @@
+package fixtures
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+type Genome []int
+
+type GeneticSearch struct {
+	MutationRate   float64
+	EliteCount     int
+	TournamentSize int
+	Workers        int
+	list           FixtureWeekList
+	rng            *rand.Rand
+	scorer         Scorer
+	population     []individual
+	generation     int
+}
+
+type individual struct {
+	genome   Genome
+	schedule Schedule
+	score    int
+}
+
+func NewGeneticSearch(fl FixtureWeekList, scorer Scorer, seed int64, size int, population ...Genome) *GeneticSearch {
+	g := &GeneticSearch{
+		MutationRate:   0.05,
+		EliteCount:     2,
+		TournamentSize: 3,
+		Workers:        1,
+		list:           fl,
+		rng:            rand.New(rand.NewSource(seed)),
+		scorer:         scorer,
+	}
+	genomes := make([]Genome, 0, size)
+	for _, genome := range population {
+		if len(genomes) < size {
+			genomes = append(genomes, g.normalise(genome))
+		}
+	}
+	for len(genomes) < size {
+		genomes = append(genomes, g.randomGenome())
+	}
+	g.population = g.evaluate(genomes)
+	return g
+}
+
+func (g *GeneticSearch) Evolve() bool {
+	previous := g.population[0].score
+	children := make([]Genome, 0, len(g.population))
+	for i := 0; i < g.EliteCount && i < len(g.population); i++ {
+		children = append(children, g.population[i].genome)
+	}
+	for len(children) < len(g.population) {
+		children = append(children, g.mutate(g.crossover(g.tournament(), g.tournament())))
+	}
+	g.population = g.evaluate(children)
+	g.generation++
+	return g.population[0].score < previous
+}
+
+func (g *GeneticSearch) Best() (Schedule, int) {
+	return g.population[0].schedule, g.population[0].score
+}
+
+func (g *GeneticSearch) Generation() int {
+	return g.generation
+}
+
+func (g *GeneticSearch) Population() []Genome {
+	answer := make([]Genome, len(g.population))
+	for i, ind := range g.population {
+		answer[i] = append(Genome{}, ind.genome...)
+	}
+	return answer
+}
+
+func (g *GeneticSearch) tournament() Genome {
+	best := g.rng.Intn(len(g.population))
+	for i := 1; i < g.TournamentSize; i++ {
+		if candidate := g.rng.Intn(len(g.population)); candidate < best {
+			best = candidate
+		}
+	}
+	return g.population[best].genome
+}
+
+func (g *GeneticSearch) crossover(parent1 Genome, parent2 Genome) Genome {
+	answer := make(Genome, len(parent1))
+	for i := range answer {
+		if g.rng.Intn(2) == 0 {
+			answer[i] = parent1[i]
+		} else {
+			answer[i] = parent2[i]
+		}
+	}
+	return answer
+}
+
+func (g *GeneticSearch) mutate(genome Genome) Genome {
+	for i := range genome {
+		if g.rng.Float64() < g.MutationRate {
+			genome[i] = g.rng.Intn(g.list.combinationCount(i))
+		}
+	}
+	return genome
+}
+
+func (g *GeneticSearch) randomGenome() Genome {
+	answer := make(Genome, len(g.list))
+	for i := range answer {
+		answer[i] = g.rng.Intn(g.list.combinationCount(i))
+	}
+	return answer
+}
+
+func (g *GeneticSearch) normalise(genome Genome) Genome {
+	answer := make(Genome, len(g.list))
+	for i := range answer {
+		if i < len(genome) {
+			answer[i] = genome[i] % g.list.combinationCount(i)
+		}
+	}
+	return answer
+}
+
+func (g *GeneticSearch) evaluate(genomes []Genome) []individual {
+	answer := make([]individual, len(genomes))
+	next := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < g.Workers || w == 0; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				schedule := g.list.Combination(genomes[i]...)
+				answer[i] = individual{genomes[i], schedule, schedule.EvaluateWith(g.scorer)}
+			}
+		}()
+	}
+	for i := range genomes {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	sort.SliceStable(answer, func(i, j int) bool {
+		return answer[i].score < answer[j].score
+	})
+	return answer
+}
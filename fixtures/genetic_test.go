@@ -0,0 +1,72 @@
+package fixtures
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCombination(t *testing.T) {
+	list := buildFixtureList(t)
+	it := list.Iterator(3, 1, 4, 1, 5)
+	expected, ok := it.Next()
+	assert.True(t, ok)
+	indices := make([]int, len(list))
+	indices[0], indices[1], indices[2], indices[3], indices[4] = 3, 1, 4, 1, 5
+	actual := list.Combination(indices...)
+	assert.Equal(t, expected.String(), actual.String())
+}
+
+func TestGeneticSearchIsReproducible(t *testing.T) {
+	run := func(seed int64, workers int) []Genome {
+		g := NewGeneticSearch(buildFixtureList(t), DefaultScorer, seed, 20)
+		g.Workers = workers
+		for i := 0; i < 10; i++ {
+			g.Evolve()
+		}
+		assert.Equal(t, 10, g.Generation())
+		return g.Population()
+	}
+	assert.Equal(t, run(42, 1), run(42, 4))
+	assert.NotEqual(t, run(42, 1), run(43, 1))
+}
+
+func TestGeneticSearchImproves(t *testing.T) {
+	g := NewGeneticSearch(buildFixtureList(t), DefaultScorer, 1, 30)
+	g.Workers = 4
+	_, initial := g.Best()
+	for i := 0; i < 30; i++ {
+		g.Evolve()
+	}
+	best, score := g.Best()
+	assert.True(t, score < initial)
+	assert.Equal(t, score, best.Evaluate())
+	population := g.Population()
+	assert.Equal(t, 30, len(population))
+	assert.Equal(t, best.String(), scheduleString(g.list.Combination(population[0]...)))
+}
+
+func TestGeneticSearchResumesPopulation(t *testing.T) {
+	list := buildFixtureList(t)
+	saved := []Genome{
+		{1, 2, 3},
+		{720, 40321},
+	}
+	g := NewGeneticSearch(list, DefaultScorer, 1, 5, saved...)
+	population := g.Population()
+	assert.Equal(t, 5, len(population))
+	found := map[string]bool{}
+	for _, genome := range population {
+		assert.Equal(t, len(list), len(genome))
+		found[scheduleString(list.Combination(genome...))] = true
+	}
+	expected1 := make(Genome, len(list))
+	expected1[0], expected1[1], expected1[2] = 1, 2, 3
+	expected2 := make(Genome, len(list))
+	expected2[1] = 1
+	assert.True(t, found[scheduleString(list.Combination(expected1...))])
+	assert.True(t, found[scheduleString(list.Combination(expected2...))])
+}
+
+func scheduleString(s Schedule) string {
+	return s.String()
+}
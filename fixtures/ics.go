@@ -0,0 +1,113 @@
+package fixtures
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// calendarDomain qualifies the UID of each event, which is made from the
+// date and teams of its match so that importing a calendar again updates
+// the events already imported rather than duplicating them.
+const calendarDomain = "fixtures.sehicl.org.uk"
+
+const icsTimeFormat = "20060102T150405Z"
+
+// WriteCalendar writes the matches of the schedule as an RFC 5545
+// calendar with the name given. Every match must have a date in the
+// calendar.
+func WriteCalendar(w io.Writer, name string, s Schedule) error {
+	stamp := time.Now().UTC().Format(icsTimeFormat)
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//SEHICL//Fixtures//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:" + icsText(name),
+	}
+	for _, m := range s {
+		if m.day.IsZero() {
+			return fmt.Errorf("%s, %v: %s v %s has no date in the calendar", m.date, m.timeslot, m.team1, m.team2)
+		}
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+m.uid(),
+			"DTSTAMP:"+stamp,
+			"DTSTART:"+m.Start().UTC().Format(icsTimeFormat),
+			"DTEND:"+m.End().UTC().Format(icsTimeFormat),
+			"SUMMARY:"+icsText(fmt.Sprintf("%s v %s", m.team1, m.team2)),
+			"LOCATION:"+icsText(m.court),
+			"END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+	bw := bufio.NewWriter(w)
+	for _, l := range lines {
+		bw.WriteString(icsFold(l))
+	}
+	return bw.Flush()
+}
+
+// WriteCalendars writes a calendar of the whole schedule to league.ics in
+// the directory given, and one of each team's matches to team-<team>.ics.
+func WriteCalendars(dir string, s Schedule) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if err := writeCalendarFile(filepath.Join(dir, "league.ics"), "League fixtures", s); err != nil {
+		return err
+	}
+	for _, ts := range s.teamSchedules() {
+		filename := filepath.Join(dir, fmt.Sprintf("team-%s.ics", lpName(ts.team)))
+		if err := writeCalendarFile(filename, fmt.Sprintf("Team %s fixtures", ts.team), ts.matches); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeCalendarFile(filename string, name string, s Schedule) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := WriteCalendar(f, name, s); err != nil {
+		f.Close()
+		return fmt.Errorf("%s: %v", filename, err)
+	}
+	return f.Close()
+}
+
+func (m *ScheduledMatch) uid() string {
+	return fmt.Sprintf("%s-%s-v-%s@%s", m.day.Format("20060102"), lpName(m.team1), lpName(m.team2), calendarDomain)
+}
+
+// icsText escapes the characters that have a meaning in a text value.
+func icsText(s string) string {
+	return strings.NewReplacer("\\", "\\\\", ";", "\\;", ",", "\\,", "\n", "\\n").Replace(s)
+}
+
+// icsFold ends the line with CRLF, first folding it so that no line is
+// longer than 75 octets, without splitting a character.
+func icsFold(line string) string {
+	var b strings.Builder
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+	return b.String()
+}
@@ -0,0 +1,68 @@
+package fixtures
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func icsTestSchedule(t *testing.T) Schedule {
+	return buildFixtureList(t).Combination(make([]int, 22)...)
+}
+
+func TestWriteCalendar(t *testing.T) {
+	var buffer bytes.Buffer
+	s := icsTestSchedule(t)
+	assert.Nil(t, WriteCalendar(&buffer, "League fixtures", s[:2]))
+	ics := buffer.String()
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(ics, "END:VEVENT\r\nEND:VCALENDAR\r\n"))
+	assert.Equal(t, 2, strings.Count(ics, "BEGIN:VEVENT\r\n"))
+	for _, expected := range []string{
+		"X-WR-CALNAME:League fixtures\r\n",
+		"UID:20180930-25-v-26@" + calendarDomain + "\r\n",
+		"DTSTART:20180930T171500Z\r\n",
+		"DTEND:20180930T181500Z\r\n",
+		"SUMMARY:25 v 26\r\n",
+		"LOCATION:A\r\n",
+		"LOCATION:B\r\n",
+	} {
+		assert.Contains(t, ics, expected)
+	}
+}
+
+func TestWriteCalendarNotDated(t *testing.T) {
+	s := FixtureWeekList{NewWeek("31 May", 6, 6, false, NewMatch("11", "12"))}.Combination(0)
+	err := WriteCalendar(&bytes.Buffer{}, "League", s)
+	assert.EqualError(t, err, "31 May, 6.15: 11 v 12 has no date in the calendar")
+}
+
+func TestWriteCalendars(t *testing.T) {
+	dir := t.TempDir()
+	s := icsTestSchedule(t)
+	assert.Nil(t, WriteCalendars(dir, s))
+	league, err := os.ReadFile(filepath.Join(dir, "league.ics"))
+	assert.Nil(t, err)
+	assert.Equal(t, len(s), strings.Count(string(league), "BEGIN:VEVENT"))
+	team, err := os.ReadFile(filepath.Join(dir, "team-25.ics"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(team), "X-WR-CALNAME:Team 25 fixtures\r\n")
+	assert.Contains(t, string(team), "UID:20180930-25-v-26@"+calendarDomain+"\r\n")
+	for _, ts := range s.teamSchedules() {
+		if ts.team == "25" {
+			assert.Equal(t, len(ts.matches), strings.Count(string(team), "BEGIN:VEVENT"))
+		}
+	}
+}
+
+func TestIcsText(t *testing.T) {
+	assert.Equal(t, "Hall 1\\, North\\; upstairs\\nback\\\\door", icsText("Hall 1, North; upstairs\nback\\door"))
+	folded := icsFold(strings.Repeat("a", 74) + "éb" + strings.Repeat("c", 80))
+	lines := strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n")
+	assert.Equal(t, strings.Repeat("a", 74), lines[0])
+	assert.Equal(t, " éb"+strings.Repeat("c", 71), lines[1])
+	assert.Equal(t, " "+strings.Repeat("c", 9), lines[2])
+}
@@ -0,0 +1,132 @@
+package fixtures
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+type seasonData struct {
+	Season
+	Courts      []string         `json:"courts,omitempty"`
+	Divisions   []Division       `json:"divisions,omitempty"`
+	Weeks       []weekData       `json:"weeks"`
+	Unavailable Unavailabilities `json:"unavailable,omitempty"`
+}
+
+type weekData struct {
+	Date string `json:"date"`
+	Slots
+	FirstTimeSingle bool       `json:"firstTimeSingle"`
+	Courts          []string   `json:"courts,omitempty"`
+	Matches         [][]string `json:"matches"`
+}
+
+func LoadFixtureList(r io.Reader) (FixtureWeekList, error) {
+	var data seasonData
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&data); err != nil {
+		return nil, err
+	}
+	if err := data.Clock.check(); err != nil {
+		return nil, err
+	}
+	if data.Courts == nil {
+		data.Courts = DefaultCourts
+	}
+	if _, err := teamDivisions(data.Divisions); err != nil {
+		return nil, err
+	}
+	answer := make(FixtureWeekList, 0, len(data.Weeks))
+	for i, wd := range data.Weeks {
+		if wd.Date == "" {
+			return nil, fmt.Errorf("week %d has no date", i+1)
+		}
+		matches := make([]*Match, 0, len(wd.Matches))
+		for j, m := range wd.Matches {
+			if len(m) != 2 {
+				return nil, fmt.Errorf("week %d (%s), match %d: expected 2 teams, found %d", i+1, wd.Date, j+1, len(m))
+			}
+			matches = append(matches, NewMatch(m[0], m[1]))
+		}
+		courts := wd.Courts
+		if courts == nil {
+			courts = data.Courts
+		}
+		week := NewWeekWithTimes(wd.Date, data.Clock.convertAll(wd.times()), wd.FirstTimeSingle, courts, matches...)
+		week.duration = wd.duration()
+		answer = append(answer, week)
+	}
+	answer, err := answer.InSeason(data.Season)
+	if err != nil {
+		return nil, err
+	}
+	for i, u := range data.Unavailable {
+		if u.Team == "" {
+			return nil, fmt.Errorf("unavailability %d has no team", i+1)
+		}
+		data.Unavailable[i].Before = data.Clock.convertRef(u.Before)
+		data.Unavailable[i].Timeslots = data.Clock.convertAll(u.Timeslots)
+		if u.Date != "" {
+			if data.Unavailable[i].Date, err = normaliseDate(u.Date); err != nil {
+				return nil, fmt.Errorf("unavailability %d: %v", i+1, err)
+			}
+		}
+	}
+	return answer.WithUnavailability(data.Unavailable).WithDivisions(data.Divisions), nil
+}
+
+func ReadFixtureList(filename string) (FixtureWeekList, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	answer, err := LoadFixtureList(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return answer, nil
+}
+
+// WriteFixtureList writes the fixture list in the format read by
+// LoadFixtureList.
+func WriteFixtureList(w io.Writer, fl FixtureWeekList) error {
+	data := seasonData{Season: fl.Season(), Weeks: make([]weekData, len(fl))}
+	courts := DefaultCourts
+	if len(fl) > 0 && fl.sameCourts() {
+		courts = fl[0].courts
+	}
+	if !sameStrings(courts, DefaultCourts) {
+		data.Courts = courts
+	}
+	data.Divisions = fl.Divisions()
+	if us := fl.Unavailability(); len(us) > 0 {
+		data.Unavailable = us
+	}
+	for i, week := range fl {
+		wd := weekData{
+			Date:    week.date,
+			Matches: make([][]string, len(week.matches)),
+		}
+		if len(week.timeslots) > 0 {
+			wd.Slots = slotsOf(week.times())
+			if week.duration != DefaultDuration {
+				wd.Duration = week.duration
+			}
+			wd.FirstTimeSingle = len(week.courts) > 1 && len(week.timeslots) > 1 && week.timeslots[0] != week.timeslots[1]
+		}
+		if !sameStrings(week.courts, courts) {
+			wd.Courts = week.courts
+		}
+		for j, m := range week.matches {
+			wd.Matches[j] = []string{m.team1, m.team2}
+		}
+		data.Weeks[i] = wd
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
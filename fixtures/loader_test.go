@@ -0,0 +1,109 @@
+package fixtures
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestLoadFixtureList(t *testing.T) {
+	data := `{"year": 2020, "weeks": [
+		{"date": "31 May", "start": 1, "end": 2, "firstTimeSingle": true,
+			"matches": [["11", "12"], ["13", "14"], ["15", "16"]]},
+		{"date": "1 Jun", "start": 3, "end": 4,
+			"matches": [["21", "22"], ["23", "24"], ["25", "26"], ["27", "28"]]}
+	]}`
+	fl, err := LoadFixtureList(strings.NewReader(data))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(fl))
+	assert.Equal(t, "31 May", fl[0].date)
+	assert.Equal(t, quarterPast(1, 2, 2), fl[0].timeslots)
+	assert.Equal(t, 3, len(fl[0].matches))
+	assert.Equal(t, "15", fl[0].matches[2].team1)
+	assert.Equal(t, "16", fl[0].matches[2].team2)
+	assert.Equal(t, "1 Jun", fl[1].date)
+	assert.Equal(t, quarterPast(3, 3, 4, 4), fl[1].timeslots)
+	assert.Equal(t, 24, fl[1].combinationCount)
+}
+
+func TestLoadFixtureListInvalid(t *testing.T) {
+	checker := func(s string) {
+		fl, err := LoadFixtureList(strings.NewReader(s))
+		assert.Nil(t, fl)
+		assert.NotNil(t, err)
+	}
+	checker("rubbish")
+	checker(`{"weeks": [{"start": 6, "end": 9, "matches": [["11", "12"], ["13", "14"]]}]}`)
+	checker(`{"weeks": [{"date": "1 Jun", "start": 6, "end": 9, "matches": [["11", "12", "13"]]}]}`)
+	checker(`{"weeks": [{"date": "1 Jun", "begin": 6}]}`)
+	checker(`{"divisions": [{"name": "1", "teams": ["11", "12"]}, {"name": "2", "teams": ["11"]}], "weeks": []}`)
+	checker(`{"clock": "12-hour", "weeks": [{"date": "1 Jun", "start": 6, "end": 9, "matches": [["11", "12"]]}]}`)
+}
+
+func TestLoadFixtureListOnEveningClock(t *testing.T) {
+	data := `{"year": 2020, "clock": "evening", "weeks": [
+		{"date": "31 May", "start": 6, "end": 7, "matches": [["11", "12"], ["13", "14"]]},
+		{"date": "1 Jun", "times": ["12.20", "1.00", "13.40"], "duration": 40, "courts": ["A"],
+			"matches": [["11", "13"], ["12", "14"], ["15", "16"]]}
+	], "unavailable": [{"team": "11", "before": "7.00", "timeslots": [8, "0.00"]}]}`
+	fl, err := LoadFixtureList(strings.NewReader(data))
+	assert.Nil(t, err)
+	assert.Equal(t, []Time{At(18, 15), At(18, 15)}, fl[0].timeslots)
+	assert.Equal(t, []Time{At(12, 20), At(13, 0), At(13, 40)}, fl[1].timeslots)
+	assert.Equal(t, Unavailabilities{{Team: "11", Before: At(19, 0).Ref(), Timeslots: []Time{At(20, 15), At(0, 0)}}}, fl.Unavailability())
+	assert.Equal(t, EveningClock, fl.Season().Clock)
+	var buffer bytes.Buffer
+	assert.Nil(t, WriteFixtureList(&buffer, fl))
+	assert.Contains(t, buffer.String(), `"start": 18`)
+	loaded, err := LoadFixtureList(&buffer)
+	assert.Nil(t, err)
+	assert.Equal(t, fl, loaded)
+}
+
+func TestReadFixtureListMatchesBuildFixtureList(t *testing.T) {
+	fl, err := ReadFixtureList("../season.json")
+	assert.Nil(t, err)
+	expected := buildFixtureList(t)
+	assert.Equal(t, len(expected), len(fl))
+	for i, w := range fl {
+		assert.Equal(t, expected[i].date, w.date)
+		assert.True(t, expected[i].day.Equal(w.day))
+		assert.Equal(t, expected[i].timeslots, w.timeslots)
+		assert.Equal(t, expected[i].matches, w.matches)
+		assert.Equal(t, expected[i].combinationCount, w.combinationCount)
+	}
+}
+
+func TestWriteFixtureList(t *testing.T) {
+	list, err := append(buildFixtureList(t), NewWeek("1 Jun", 5, 6, true, NewMatch("11", "12"), NewMatch("13", "14"))).
+		InSeason(Season{Year: 2018, TimeZone: "Europe/London"})
+	assert.Nil(t, err)
+	list = list.WithDivisions(list.Divisions())
+	var buffer bytes.Buffer
+	assert.Nil(t, WriteFixtureList(&buffer, list))
+	loaded, err := LoadFixtureList(&buffer)
+	assert.Nil(t, err)
+	assert.Equal(t, list, loaded)
+}
+
+func TestLoadFixtureListWithCourts(t *testing.T) {
+	data := `{"year": 2020, "courts": ["North", "South", "East"], "weeks": [
+		{"date": "31 May", "start": 6, "end": 7, "firstTimeSingle": true,
+			"matches": [["11", "12"], ["13", "14"], ["15", "16"]]},
+		{"date": "1 Jun", "start": 6, "end": 7, "courts": ["North"],
+			"matches": [["11", "13"], ["12", "15"]]}
+	]}`
+	fl, err := LoadFixtureList(strings.NewReader(data))
+	assert.Nil(t, err)
+	assert.Equal(t, quarterPast(6, 7, 7), fl[0].timeslots)
+	assert.Equal(t, "South", fl[0].court(2))
+	assert.Equal(t, quarterPast(6, 7), fl[1].timeslots)
+	assert.Equal(t, []string{"North", "South", "East"}, fl.Courts())
+	var buffer bytes.Buffer
+	assert.Nil(t, WriteFixtureList(&buffer, fl))
+	assert.Contains(t, buffer.String(), `"courts": [`)
+	loaded, err := LoadFixtureList(&buffer)
+	assert.Nil(t, err)
+	assert.Equal(t, fl, loaded)
+}
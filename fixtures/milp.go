@@ -0,0 +1,253 @@
+package fixtures
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type lpWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (lw *lpWriter) printf(format string, args ...interface{}) {
+	if lw.err == nil {
+		_, lw.err = fmt.Fprintf(lw.w, format, args...)
+	}
+}
+
+func (lw *lpWriter) constraint(name string, terms []lpTerm, rest string) {
+	lw.printf(" %s:", name)
+	for i, t := range terms {
+		if i > 0 && i%8 == 0 {
+			lw.printf("\n  ")
+		}
+		coefficient := t.coefficient
+		if coefficient < 0 {
+			lw.printf(" -")
+			coefficient = -coefficient
+		} else if i > 0 {
+			lw.printf(" +")
+		}
+		if coefficient != 1 {
+			lw.printf(" %d", coefficient)
+		}
+		lw.printf(" %s", t.variable)
+	}
+	lw.printf(" %s\n", rest)
+}
+
+func (lw *lpWriter) variables(section string, names []string) {
+	lw.printf("%s\n", section)
+	for i := 0; i < len(names); i += 8 {
+		end := i + 8
+		if end > len(names) {
+			end = len(names)
+		}
+		lw.printf(" %s\n", strings.Join(names[i:end], " "))
+	}
+}
+
+type lpTerm struct {
+	coefficient int
+	variable    string
+}
+
+func terms(coefficient int, variables ...string) []lpTerm {
+	answer := make([]lpTerm, len(variables))
+	for i, v := range variables {
+		answer[i] = lpTerm{coefficient, v}
+	}
+	return answer
+}
+
+type teamSlots struct {
+	matches     int
+	values      map[string]map[string][]string
+	unavailable []string
+}
+
+// WriteLP writes the search as a mixed integer program in CPLEX LP format.
+// Variable x_w_m_p is 1 when match m of week w is played in position p of
+// that week's timeslots, and the objective is the score of the worst team.
+// As in the scorer, a team playing when unavailable adds the unavailable
+// penalty to its score rather than ruling the arrangement out, so that a
+// clash that cannot be avoided still leaves a solution.
+func (fl FixtureWeekList) WriteLP(w io.Writer, config ScoringConfig) error {
+	lw := &lpWriter{w: bufio.NewWriter(w)}
+	lw.printf("\\ Fixture schedule: minimise the worst team's score\nMinimize\n obj: z\nSubject To\n")
+	teams := make(map[string]*teamSlots)
+	team := func(name string) *teamSlots {
+		ts, found := teams[name]
+		if !found {
+			ts = &teamSlots{values: make(map[string]map[string][]string)}
+			for _, attribute := range attributeNames {
+				ts.values[attribute] = make(map[string][]string)
+			}
+			teams[name] = ts
+		}
+		return ts
+	}
+	binaries := make([]string, 0)
+	for wi, week := range fl {
+		positions := make([][]string, len(week.matches))
+		for mi, m := range week.matches {
+			vars := make([]string, len(week.matches))
+			for p := range week.matches {
+				x := fmt.Sprintf("x_%d_%d_%d", wi, mi, p)
+				vars[p] = x
+				positions[p] = append(positions[p], x)
+				sm := week.schedule(m, week.timeslots[p], week.court(p))
+				if sm.unavailable[0] {
+					team(m.team1).unavailable = append(team(m.team1).unavailable, x)
+				}
+				if sm.unavailable[1] {
+					team(m.team2).unavailable = append(team(m.team2).unavailable, x)
+				}
+				for _, t := range []string{m.team1, m.team2} {
+					for attribute, values := range team(t).values {
+						if v := config.attribute(attribute)(sm); v != "" {
+							values[v] = append(values[v], x)
+						}
+					}
+				}
+			}
+			team(m.team1).matches++
+			team(m.team2).matches++
+			binaries = append(binaries, vars...)
+			lw.constraint(fmt.Sprintf("match_%d_%d", wi, mi), terms(1, vars...), "= 1")
+		}
+		for p, vars := range positions {
+			lw.constraint(fmt.Sprintf("position_%d_%d", wi, p), terms(1, vars...), "= 1")
+		}
+	}
+	names := make([]string, 0, len(teams))
+	for name := range teams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	generals := []string{"z"}
+	for ti, name := range names {
+		ts := teams[name]
+		p := fmt.Sprintf("p_%d", ti)
+		score := []lpTerm{{1, "z"}, {-config.CapPenalty, p}}
+		if config.UnavailablePenalty != 0 {
+			score = append(score, terms(-config.UnavailablePenalty, ts.unavailable...)...)
+		}
+		lw.printf("\\ Team %s\n", name)
+		for ci, c := range config.Criteria {
+			max, min := fmt.Sprintf("max_%d_%d", ci, ti), fmt.Sprintf("min_%d_%d", ci, ti)
+			values := make(map[string][]string)
+			always := make(map[string]bool)
+			for _, v := range c.Values {
+				values[v] = nil
+				always[v] = true
+			}
+			for v, vars := range ts.values[c.Attribute] {
+				values[v] = vars
+			}
+			for _, v := range sortedStrings(values) {
+				vars := values[v]
+				key := fmt.Sprintf("%d_%d_%s", ci, ti, lpName(v))
+				lw.constraint("max_"+key, append(terms(1, max), terms(-1, vars...)...), ">= 0")
+				if always[v] {
+					lw.constraint("min_"+key, append(terms(1, min), terms(-1, vars...)...), "<= 0")
+					continue
+				}
+				y := "y_" + key
+				binaries = append(binaries, y)
+				lw.constraint("min_"+key, append([]lpTerm{{1, min}, {ts.matches, y}}, terms(-1, vars...)...), fmt.Sprintf("<= %d", ts.matches))
+				lw.constraint("used_"+key, append(terms(1, vars...), lpTerm{-ts.matches, y}), "<= 0")
+				lw.constraint("unused_"+key, append(terms(1, y), terms(-1, vars...)...), "<= 0")
+			}
+			score = append(score, lpTerm{-c.Weight, max}, lpTerm{c.Weight, min})
+			generals = append(generals, max, min)
+		}
+		for ci, c := range config.Caps {
+			vars := ts.values["period"][c.Period]
+			if c.Timeslot != nil {
+				vars = ts.values["timeslot"][c.Timeslot.String()]
+			}
+			if len(vars) > c.Max {
+				lw.constraint(fmt.Sprintf("cap_%d_%d", ci, ti), append(terms(1, vars...), lpTerm{-ts.matches, p}), fmt.Sprintf("<= %d", c.Max))
+			}
+		}
+		lw.constraint(fmt.Sprintf("score_%d", ti), score, ">= 0")
+		binaries = append(binaries, p)
+	}
+	lw.variables("General", generals)
+	lw.variables("Binary", binaries)
+	lw.printf("End\n")
+	if lw.err != nil {
+		return lw.err
+	}
+	return lw.w.Flush()
+}
+
+// ReadSolution rebuilds a schedule from a solver's solution file. Any
+// format which lists each x variable followed by its value is accepted,
+// which covers the solution files written by both CBC and HiGHS.
+func (fl FixtureWeekList) ReadSolution(r io.Reader) (Schedule, error) {
+	assigned := make([][]*Match, len(fl))
+	for wi, w := range fl {
+		assigned[wi] = make([]*Match, len(w.matches))
+	}
+	variable, _ := regexp.Compile("^x_(\\d+)_(\\d+)_(\\d+)$")
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		for i, f := range fields {
+			groups := variable.FindStringSubmatch(f)
+			if groups == nil || i+1 >= len(fields) {
+				continue
+			}
+			value, err := strconv.ParseFloat(fields[i+1], 64)
+			if err != nil || value < 0.5 {
+				break
+			}
+			wi, _ := strconv.Atoi(groups[1])
+			mi, _ := strconv.Atoi(groups[2])
+			p, _ := strconv.Atoi(groups[3])
+			if wi >= len(fl) || mi >= len(fl[wi].matches) || p >= len(fl[wi].matches) {
+				return nil, fmt.Errorf("variable %s does not belong to this fixture list", f)
+			}
+			if assigned[wi][p] != nil {
+				return nil, fmt.Errorf("week %d (%s): position %d is assigned more than one match", wi+1, fl[wi].date, p+1)
+			}
+			assigned[wi][p] = fl[wi].matches[mi]
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	answer := make(Schedule, 0)
+	for wi, w := range fl {
+		for p, m := range assigned[wi] {
+			if m == nil {
+				return nil, fmt.Errorf("week %d (%s): no match is assigned to position %d", wi+1, w.date, p+1)
+			}
+			answer = append(answer, w.schedule(m, w.timeslots[p], w.court(p)))
+		}
+	}
+	return answer, nil
+}
+
+func lpName(s string) string {
+	r, _ := regexp.Compile("[^A-Za-z0-9_]")
+	return r.ReplaceAllString(s, "_")
+}
+
+func sortedStrings(m map[string][]string) []string {
+	answer := make([]string, 0, len(m))
+	for k := range m {
+		answer = append(answer, k)
+	}
+	sort.Strings(answer)
+	return answer
+}
@@ -0,0 +1,135 @@
+package fixtures
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func milpTestList() FixtureWeekList {
+	return FixtureWeekList{
+		NewWeek("31 May", 5, 6, true,
+			NewMatch("11", "12"),
+			NewMatch("13", "14"),
+			NewMatch("15", "16")),
+		NewWeek("1 Jun", 6, 7, false,
+			NewMatch("11", "13"),
+			NewMatch("12", "15"),
+			NewMatch("14", "16")),
+	}
+}
+
+func TestWriteLP(t *testing.T) {
+	var buffer bytes.Buffer
+	assert.Nil(t, milpTestList().WriteLP(&buffer, DefaultScoringConfig()))
+	lp := buffer.String()
+	assert.True(t, strings.HasPrefix(lp, "\\ Fixture schedule"))
+	assert.True(t, strings.HasSuffix(lp, "End\n"))
+	for _, expected := range []string{
+		"Minimize\n obj: z\n",
+		" match_0_1: x_0_1_0 + x_0_1_1 + x_0_1_2 = 1\n",
+		" position_1_2: x_1_0_2 + x_1_1_2 + x_1_2_2 = 1\n",
+		" max_0_0_6_15: max_0_0 - x_0_0_1 - x_0_0_2 - x_1_0_0 - x_1_0_1 >= 0\n",
+		" min_0_0_5_15: min_0_0 + 2 y_0_0_5_15 - x_0_0_0 <= 2\n",
+		" used_0_0_5_15: x_0_0_0 - 2 y_0_0_5_15 <= 0\n",
+		" min_1_0_B: min_1_0 - x_0_0_2 - x_1_0_1 <= 0\n",
+		" score_0: z - 100 p_0 - 10 max_0_0 + 10 min_0_0 - max_1_0 + min_1_0 >= 0\n",
+		"General\n z max_0_0 min_0_0 max_1_0 min_1_0",
+		"Binary\n x_0_0_0 x_0_0_1",
+	} {
+		assert.Contains(t, lp, expected)
+	}
+	assert.NotContains(t, lp, "cap_")
+}
+
+func TestWriteLPWithScoringConfig(t *testing.T) {
+	var buffer bytes.Buffer
+	config := ScoringConfig{
+		Criteria:   []Criterion{{Attribute: "court", Weight: 3, Values: []string{"A", "B"}}},
+		Caps:       []Cap{{Timeslot: At(6, 15).Ref(), Max: 1}},
+		CapPenalty: 50,
+	}
+	assert.Nil(t, milpTestList().WriteLP(&buffer, config))
+	lp := buffer.String()
+	for _, expected := range []string{
+		" cap_0_0: x_0_0_1 + x_0_0_2 + x_1_0_0 + x_1_0_1 - 2 p_0 <= 1\n",
+		" score_0: z - 50 p_0 - 3 max_0_0 + 3 min_0_0 >= 0\n",
+		"General\n z max_0_0 min_0_0 max_0_1 min_0_1",
+	} {
+		assert.Contains(t, lp, expected)
+	}
+	assert.NotContains(t, lp, "y_")
+}
+
+func TestReadSolution(t *testing.T) {
+	list := milpTestList()
+	highs := `Model status
+Optimal
+
+# Primal solution values
+Feasible
+Objective 21
+# Columns 4
+x_0_0_0 0
+x_0_0_1 1
+x_0_1_0 1
+x_0_1_2 0
+x_0_2_2 1
+x_1_0_0 1
+x_1_1_1 1
+x_1_2_2 1
+tmax_0 2
+`
+	cbc := `Optimal - objective value 21.00000000
+      1 x_0_0_1                  1                       0
+      2 x_0_1_0                  1                       0
+      8 x_0_2_2                  1                       0
+      9 x_1_0_0                  1                       0
+     13 x_1_1_1                  1                       0
+     17 x_1_2_2                  1                       0
+`
+	expected := "31 May, 5.15, A: 13 v 14\n31 May, 6.15, A: 11 v 12\n31 May, 6.15, B: 15 v 16\n" +
+		"1 Jun, 6.15, A: 11 v 13\n1 Jun, 6.15, B: 12 v 15\n1 Jun, 7.15, A: 14 v 16\n"
+	for _, solution := range []string{highs, cbc} {
+		s, err := list.ReadSolution(strings.NewReader(solution))
+		assert.Nil(t, err)
+		assert.Equal(t, expected, s.String())
+	}
+}
+
+func TestReadSolutionNotValid(t *testing.T) {
+	list := milpTestList()
+	checker := func(solution string, message string) {
+		s, err := list.ReadSolution(strings.NewReader(solution))
+		assert.Nil(t, s)
+		assert.EqualError(t, err, message)
+	}
+	checker("x_0_0_0 1\nx_0_1_0 1\n", "week 1 (31 May): position 1 is assigned more than one match")
+	checker("x_0_0_0 1\n", "week 1 (31 May): no match is assigned to position 2")
+	checker("x_2_0_0 1\n", "variable x_2_0_0 does not belong to this fixture list")
+}
+
+func TestWriteLPWithUnavailability(t *testing.T) {
+	var buffer bytes.Buffer
+	list := milpTestList().WithUnavailability(Unavailabilities{{Team: "16", Date: "1 Jun", Timeslots: quarterPast(7)}})
+	assert.Nil(t, list.WriteLP(&buffer, DefaultScoringConfig()))
+	lp := buffer.String()
+	assert.Contains(t, lp, " score_5: z - 100 p_5 - 1000 x_1_2_2 - 10 max_0_5 + 10 min_0_5")
+	assert.Contains(t, lp, " score_4: z - 100 p_4 - 10 max_0_4")
+	assert.NotContains(t, lp, "unavailable_")
+}
+
+func TestWriteLPWithUnavoidableUnavailability(t *testing.T) {
+	var buffer bytes.Buffer
+	list := milpTestList().WithUnavailability(Unavailabilities{{Team: "16", Date: "1 Jun"}})
+	config := DefaultScoringConfig()
+	config.UnavailablePenalty = 500
+	assert.Nil(t, list.WriteLP(&buffer, config))
+	lp := buffer.String()
+	// Every position of 14 v 16 on 1 Jun is penalised rather than ruled
+	// out, which would leave the match nowhere to go.
+	assert.Contains(t, lp, " score_5: z - 100 p_5 - 500 x_1_2_0 - 500 x_1_2_1 - 500 x_1_2_2 - 10 max_0_5")
+	assert.Contains(t, lp, " match_1_2: x_1_2_0 + x_1_2_1 + x_1_2_2 = 1\n")
+	assert.NotContains(t, lp, " = 0\n")
+}
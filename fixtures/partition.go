@@ -0,0 +1,36 @@
+package fixtures
+
+func (fl *FixtureWeekList) Partition(parts int, startIndices ...int) []*FixtureListIterator {
+	return fl.Iterator(startIndices...).Split(parts)
+}
+
+func (it *FixtureListIterator) Split(parts int) []*FixtureListIterator {
+	start := it.nextIndices[0]
+	if remaining := it.Remaining(); parts > remaining {
+		parts = remaining
+	}
+	if parts <= 1 {
+		return []*FixtureListIterator{it}
+	}
+	bounds := make([]int, parts+1)
+	for i := range bounds {
+		bounds[i] = start + it.Remaining()*i/parts
+	}
+	answer := make([]*FixtureListIterator, parts)
+	answer[0] = it.list.RangeIterator(it.first, bounds[1], it.nextIndices...)
+	for i := 1; i < parts; i++ {
+		answer[i] = it.list.RangeIterator(bounds[i], bounds[i+1], bounds[i])
+	}
+	return answer
+}
+
+func (it *FixtureListIterator) Range() (first int, last int) {
+	return it.first, it.last
+}
+
+func (it *FixtureListIterator) Remaining() int {
+	if it.nextIndices[0] >= it.last {
+		return 0
+	}
+	return it.last - it.nextIndices[0]
+}
@@ -0,0 +1,79 @@
+package fixtures
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func smallFixtureList() FixtureWeekList {
+	return FixtureWeekList{
+		NewWeek("31 May", 1, 2, true,
+			NewMatch("11", "12"),
+			NewMatch("13", "14"),
+			NewMatch("15", "16")),
+		NewWeek("1 Jun", 3, 4, true,
+			NewMatch("21", "22"),
+			NewMatch("23", "24"),
+			NewMatch("25", "26")),
+		NewWeek("2 Jun", 5, 6, true,
+			NewMatch("31", "32"),
+			NewMatch("33", "34"),
+			NewMatch("35", "36")),
+	}
+}
+
+func countIterations(it *FixtureListIterator, seen map[string]bool) int {
+	answer := 0
+	for s, ok := it.Next(); ok; s, ok = it.Next() {
+		seen[s.String()] = true
+		answer++
+	}
+	return answer
+}
+
+func TestPartition(t *testing.T) {
+	list := smallFixtureList()
+	parts := list.Partition(4)
+	assert.Equal(t, 4, len(parts))
+	ranges := make([][2]int, len(parts))
+	seen := make(map[string]bool)
+	total := 0
+	for i, it := range parts {
+		first, last := it.Range()
+		ranges[i] = [2]int{first, last}
+		total += countIterations(it, seen)
+	}
+	assert.Equal(t, [][2]int{{0, 1}, {1, 3}, {3, 4}, {4, 6}}, ranges)
+	assert.Equal(t, 216, total)
+	assert.Equal(t, 216, len(seen))
+}
+
+func TestPartitionFromStartPosition(t *testing.T) {
+	list := smallFixtureList()
+	parts := list.Partition(2, 2, 3, 1)
+	assert.Equal(t, 2, len(parts))
+	assert.Equal(t, []int{2, 3, 1}, parts[0].NextIndices())
+	assert.Equal(t, []int{4, 0, 0}, parts[1].NextIndices())
+	seen := make(map[string]bool)
+	assert.Equal(t, 53, countIterations(parts[0], seen))
+	assert.Equal(t, 72, countIterations(parts[1], seen))
+	assert.Equal(t, 125, len(seen))
+}
+
+func TestPartitionMorePartsThanIndices(t *testing.T) {
+	list := smallFixtureList()
+	parts := list.Partition(10, 4)
+	assert.Equal(t, 2, len(parts))
+	assert.Equal(t, 0, list.Partition(3, 6)[0].Remaining())
+}
+
+func TestRangeIteratorFinished(t *testing.T) {
+	list := smallFixtureList()
+	it := list.RangeIterator(0, 2, 2, 0, 0)
+	assert.Equal(t, 0, it.Remaining())
+	_, ok := it.Next()
+	assert.False(t, ok)
+	it = list.RangeIterator(3, 5, 1, 4, 4)
+	assert.Equal(t, []int{3, 0, 0}, it.NextIndices())
+	assert.Equal(t, 2, it.Remaining())
+}
@@ -0,0 +1,156 @@
+package fixtures
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type CriterionReport struct {
+	Attribute string
+	Values    []string
+	Counts    []int
+	Imbalance int
+	Weight    int
+	Score     int
+}
+
+type TeamReport struct {
+	Team               string
+	Matches            int
+	Criteria           []CriterionReport
+	ExceededCaps       []Cap
+	Penalty            int
+	Unavailable        int
+	UnavailablePenalty int
+	Score              int
+}
+
+type Report []*TeamReport
+
+// Report explains the score of each team in the schedule under the given
+// scoring, worst team first.
+func (s *Schedule) Report(config ScoringConfig) Report {
+	answer := make(Report, 0)
+	for _, ts := range s.teamSchedules() {
+		tr := &TeamReport{
+			Team:         ts.team,
+			Matches:      len(ts.matches),
+			Criteria:     make([]CriterionReport, len(config.Criteria)),
+			ExceededCaps: config.exceededCaps(ts),
+		}
+		if len(tr.ExceededCaps) > 0 {
+			tr.Penalty = config.CapPenalty
+		}
+		tr.Unavailable = ts.unavailableCount()
+		tr.UnavailablePenalty = config.UnavailablePenalty * tr.Unavailable
+		tr.Score = tr.Penalty + tr.UnavailablePenalty
+		for i, c := range config.Criteria {
+			values, counts := c.counts(ts, config.attribute(c.Attribute))
+			imbalance := imbalanceOf(counts)
+			tr.Criteria[i] = CriterionReport{
+				Attribute: c.Attribute,
+				Values:    values,
+				Counts:    counts,
+				Imbalance: imbalance,
+				Weight:    c.Weight,
+				Score:     c.Weight * imbalance,
+			}
+			tr.Score += c.Weight * imbalance
+		}
+		answer = append(answer, tr)
+	}
+	sort.SliceStable(answer, func(i, j int) bool {
+		return answer[i].Score > answer[j].Score
+	})
+	return answer
+}
+
+func (r Report) String() string {
+	var buffer bytes.Buffer
+	for _, tr := range r {
+		buffer.WriteString(tr.String())
+	}
+	return buffer.String()
+}
+
+func (tr *TeamReport) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString(fmt.Sprintf("Team %s: score %d from %d matches\n", tr.Team, tr.Score, tr.Matches))
+	for _, cr := range tr.Criteria {
+		counts := make([]string, len(cr.Values))
+		for i, v := range cr.Values {
+			counts[i] = fmt.Sprintf("%s=%d", v, cr.Counts[i])
+		}
+		buffer.WriteString(fmt.Sprintf("  %s: %s, imbalance %d x %d = %d\n",
+			cr.Attribute, strings.Join(counts, " "), cr.Imbalance, cr.Weight, cr.Score))
+	}
+	for _, c := range tr.ExceededCaps {
+		buffer.WriteString(fmt.Sprintf("  more than %d matches %s\n", c.Max, c.when()))
+	}
+	if len(tr.ExceededCaps) > 0 {
+		buffer.WriteString(fmt.Sprintf("  penalty %d\n", tr.Penalty))
+	}
+	if tr.Unavailable > 0 {
+		buffer.WriteString(fmt.Sprintf("  %d matches when unavailable, penalty %d\n", tr.Unavailable, tr.UnavailablePenalty))
+	}
+	return buffer.String()
+}
+
+// LoadSchedule reads a schedule in the format written by Schedule.String.
+// Lines holding only numbers, such as the score at the top of the best
+// file, are skipped, as is a line holding a JSON object, such as the header
+// that records the search which wrote the best file.
+func LoadSchedule(r io.Reader) (Schedule, error) {
+	line, _ := regexp.Compile("^(.+), (\\d+[.:]\\d\\d), ([^:]+): (\\S+) v (\\S+)$")
+	number, _ := regexp.Compile("^[\\d ]+$")
+	answer := make(Schedule, 0)
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || number.MatchString(text) || strings.HasPrefix(text, "{") {
+			continue
+		}
+		groups := line.FindStringSubmatch(text)
+		if groups == nil {
+			return nil, fmt.Errorf("line %d: %q is not a scheduled match", n, text)
+		}
+		timeslot, err := ParseTime(groups[2])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+		answer = append(answer, NewScheduledMatch(NewMatch(groups[4], groups[5]), groups[1], timeslot, groups[3]))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return answer, nil
+}
+
+// ReadSchedule reads a schedule file in the format its extension gives:
+// CSV for .csv, JSON for .json and otherwise that of Schedule.String.
+func ReadSchedule(filename string) (Schedule, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var answer Schedule
+	switch scheduleFormatOf(filename) {
+	case CSVFormat:
+		answer, err = LoadScheduleCSV(f)
+	case JSONFormat:
+		answer, err = LoadScheduleJSON(f)
+	default:
+		answer, err = LoadSchedule(f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return answer, nil
+}
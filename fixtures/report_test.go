@@ -0,0 +1,59 @@
+package fixtures
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	s, err := LoadSchedule(strings.NewReader(`8
+31 May, 17.15, A: 11 v 12
+31 May, 17.15, B: 13 v 14
+1 Jun, 17.15, A: 11 v 13
+1 Jun, 18.15, A: 12 v 14
+`))
+	assert.Nil(t, err)
+	r := s.Report(DefaultScoringConfig())
+	assert.Equal(t, 4, len(r))
+	assert.Equal(t, "11", r[0].Team)
+	assert.Equal(t, 122, r[0].Score)
+	assert.Equal(t, []Cap{{Timeslot: At(17, 15).Ref(), Max: 1}}, r[0].ExceededCaps)
+	assert.Equal(t, []string{"18.15", "19.15", "20.15", "21.15", "17.15"}, r[0].Criteria[0].Values)
+	assert.Equal(t, []int{0, 0, 0, 0, 2}, r[0].Criteria[0].Counts)
+	for _, tr := range r {
+		ts := s.teamSchedules()
+		for _, other := range ts {
+			if other.team == tr.Team {
+				assert.Equal(t, other.evaluate(), tr.Score)
+			}
+		}
+	}
+	for i := 1; i < len(r); i++ {
+		assert.True(t, r[i-1].Score >= r[i].Score)
+	}
+	assert.Equal(t, "Team 11: score 122 from 2 matches\n"+
+		"  timeslot: 18.15=0 19.15=0 20.15=0 21.15=0 17.15=2, imbalance 2 x 10 = 20\n"+
+		"  court: A=2 B=0, imbalance 2 x 1 = 2\n"+
+		"  more than 1 matches at 17.15\n"+
+		"  penalty 100\n", r[0].String())
+}
+
+func TestLoadSchedule(t *testing.T) {
+	list := buildFixtureList(t)
+	s, ok := list.Iterator().Next()
+	assert.True(t, ok)
+	loaded, err := LoadSchedule(strings.NewReader("164\n" + s.String()))
+	assert.Nil(t, err)
+	assert.Equal(t, s.String(), loaded.String())
+	assert.Equal(t, 164, loaded.Evaluate())
+	loaded, err = LoadSchedule(strings.NewReader("{\"version\":1}\n164\n" + s.String()))
+	assert.Nil(t, err)
+	assert.Equal(t, s.String(), loaded.String())
+}
+
+func TestLoadScheduleNotValid(t *testing.T) {
+	s, err := LoadSchedule(strings.NewReader("31 May, 5.15, A: 11 v 12\n31 May: 11 v 13\n"))
+	assert.Nil(t, s)
+	assert.EqualError(t, err, "line 2: \"31 May: 11 v 13\" is not a scheduled match")
+}
@@ -0,0 +1,267 @@
+package fixtures
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+type Division struct {
+	Name  string   `json:"name"`
+	Teams []string `json:"teams"`
+}
+
+type Session struct {
+	Date string `json:"date"`
+	Slots
+	FirstTimeSingle bool     `json:"firstTimeSingle"`
+	Courts          []string `json:"courts,omitempty"`
+}
+
+func (s *Session) courts() []string {
+	if s.Courts == nil {
+		return DefaultCourts
+	}
+	return s.Courts
+}
+
+func (s *Session) capacity() int {
+	answer := len(s.courts()) * len(s.times())
+	if s.FirstTimeSingle && len(s.courts()) > 0 {
+		answer -= len(s.courts()) - 1
+	}
+	return answer
+}
+
+// League describes a season to be generated: each pair of teams in a
+// division meets Meetings times, on the dates of the sessions available.
+// The dates are placed in the calendar as the Season describes.
+type League struct {
+	Season
+	Divisions []Division `json:"divisions"`
+	Dates     []Session  `json:"dates"`
+	Meetings  int        `json:"meetings"`
+}
+
+func LoadLeague(r io.Reader) (*League, error) {
+	var answer League
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&answer); err != nil {
+		return nil, err
+	}
+	return &answer, nil
+}
+
+func ReadLeague(filename string) (*League, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	answer, err := LoadLeague(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return answer, nil
+}
+
+// Generate builds the fixture list for the league. Each division's rounds
+// are spread evenly over the dates, in order, while keeping each date
+// within the number of matches its session can hold.
+func (l *League) Generate() (FixtureWeekList, error) {
+	if err := l.check(); err != nil {
+		return nil, err
+	}
+	weeks := make([][]*Match, len(l.Dates))
+	load := make([]int, len(l.Dates))
+	for _, d := range l.Divisions {
+		rounds := roundRobin(d.Teams, l.Meetings)
+		if len(rounds) > len(l.Dates) {
+			return nil, fmt.Errorf("division %s: %d rounds to play but only %d dates", d.Name, len(rounds), len(l.Dates))
+		}
+		window := len(l.Dates) / (2 * len(rounds))
+		previous := -1
+		for i, round := range rounds {
+			date := l.chooseDate(load, len(round), previous, len(rounds)-i, (2*i+1)*len(l.Dates)/(2*len(rounds)), window)
+			if date < 0 {
+				return nil, fmt.Errorf("division %s: no date left for round %d of %d", d.Name, i+1, len(rounds))
+			}
+			weeks[date] = append(weeks[date], round...)
+			load[date] += len(round)
+			previous = date
+		}
+	}
+	answer := make(FixtureWeekList, 0, len(l.Dates))
+	for i, s := range l.Dates {
+		if len(weeks[i]) > 0 {
+			week := NewWeekWithTimes(s.Date, l.Clock.convertAll(s.times()), s.FirstTimeSingle, s.courts(), weeks[i]...)
+			week.duration = s.duration()
+			answer = append(answer, week)
+		}
+	}
+	answer, err := answer.InSeason(l.Season)
+	if err != nil {
+		return nil, err
+	}
+	return answer.WithDivisions(l.Divisions), nil
+}
+
+func (l *League) check() error {
+	if l.Meetings < 1 {
+		return fmt.Errorf("meetings must be at least 1, found %d", l.Meetings)
+	}
+	if len(l.Dates) == 0 {
+		return fmt.Errorf("no dates available")
+	}
+	for i, s := range l.Dates {
+		if s.Date == "" {
+			return fmt.Errorf("date %d has no date", i+1)
+		}
+		if _, err := ParseDate(s.Date); err != nil {
+			return fmt.Errorf("date %d: %v", i+1, err)
+		}
+		if s.capacity() < 1 {
+			return fmt.Errorf("date %d (%s): no timeslots or courts available", i+1, s.Date)
+		}
+	}
+	for i, d := range l.Divisions {
+		if len(d.Teams) < 2 {
+			return fmt.Errorf("division %d (%s): expected at least 2 teams, found %d", i+1, d.Name, len(d.Teams))
+		}
+	}
+	_, err := teamDivisions(l.Divisions)
+	return err
+}
+
+// teamDivisions maps each team to the name of its division, and returns
+// an error for the first team with no name or in more than one division.
+func teamDivisions(ds []Division) (map[string]string, error) {
+	if len(ds) == 0 {
+		return nil, nil
+	}
+	answer := make(map[string]string)
+	var err error
+	for i, d := range ds {
+		for _, t := range d.Teams {
+			other, found := answer[t]
+			switch {
+			case t == "" && err == nil:
+				err = fmt.Errorf("division %d (%s): team with no name", i+1, d.Name)
+			case found && err == nil:
+				err = fmt.Errorf("division %d (%s): team %s is already in division %s", i+1, d.Name, t, other)
+			case !found:
+				answer[t] = d.Name
+			}
+		}
+	}
+	return answer, err
+}
+
+// WithDivisions returns a copy of the fixture list in which each week
+// knows the division of each team, so that its matches are exported with
+// their division.
+func (fl FixtureWeekList) WithDivisions(ds []Division) FixtureWeekList {
+	divisions, _ := teamDivisions(ds)
+	answer := make(FixtureWeekList, len(fl))
+	for i, w := range fl {
+		week := *w
+		week.divisions, week.divisionOf = ds, divisions
+		answer[i] = &week
+	}
+	return answer
+}
+
+// Divisions returns the divisions given to the fixture list.
+func (fl FixtureWeekList) Divisions() []Division {
+	if len(fl) == 0 {
+		return nil
+	}
+	return fl[0].divisions
+}
+
+// chooseDate picks the date for a round of the given size, after the
+// previous round's date and leaving enough dates for the rounds still to
+// come. The emptiest date within the window around the ideal one is
+// preferred, so that divisions are spread across the dates, and otherwise
+// the date nearest the ideal one.
+func (l *League) chooseDate(load []int, size int, previous int, remaining int, ideal int, window int) int {
+	answer := -1
+	cost := func(date int) int {
+		if d := distance(date, ideal); d > window {
+			return d
+		}
+		return 0
+	}
+	for date := previous + 1; date <= len(l.Dates)-remaining; date++ {
+		if load[date]+size > l.Dates[date].capacity() {
+			continue
+		}
+		if answer < 0 || cost(date) < cost(answer) ||
+			cost(date) == cost(answer) && load[date] < load[answer] {
+			answer = date
+		}
+	}
+	return answer
+}
+
+func distance(a int, b int) int {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// roundRobin returns the rounds in which each pair of teams meets the given
+// number of times, using the circle method. In a single round robin no
+// team is at home more than once more than it is away, and home and away
+// are reversed in every second one.
+func roundRobin(teams []string, meetings int) [][]*Match {
+	circle := append([]string{}, teams...)
+	odd := len(circle)%2 == 1
+	if odd {
+		circle = append(circle, "")
+	}
+	positions := make(map[string]int, len(teams))
+	for i, t := range teams {
+		positions[t] = i
+	}
+	n := len(circle)
+	single := make([][]*Match, 0, n-1)
+	for r := 0; r < n-1; r++ {
+		round := make([]*Match, 0, n/2)
+		for i := 0; i < n/2; i++ {
+			home, away := circle[i], circle[n-1-i]
+			if odd {
+				// Every team plays an even number of matches, which this
+				// splits exactly between home and away.
+				if (positions[home]+positions[away])%2 == 0 == (positions[home] < positions[away]) {
+					home, away = away, home
+				}
+			} else if i == 0 && r%2 == 1 || i > 0 && i%2 == 1 {
+				home, away = away, home
+			}
+			if home != "" && away != "" {
+				round = append(round, NewMatch(home, away))
+			}
+		}
+		single = append(single, round)
+		circle = append(circle[:1], append([]string{circle[n-1]}, circle[1:n-1]...)...)
+	}
+	answer := make([][]*Match, 0, len(single)*meetings)
+	for m := 0; m < meetings; m++ {
+		for _, round := range single {
+			if m%2 == 0 {
+				answer = append(answer, round)
+				continue
+			}
+			reversed := make([]*Match, len(round))
+			for i, match := range round {
+				reversed[i] = NewMatch(match.team2, match.team1)
+			}
+			answer = append(answer, reversed)
+		}
+	}
+	return answer
+}
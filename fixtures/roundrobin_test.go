@@ -0,0 +1,135 @@
+package fixtures
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRoundRobin(t *testing.T) {
+	for n := 2; n <= 11; n++ {
+		teams := make([]string, n)
+		for i := range teams {
+			teams[i] = strconv.Itoa(i + 1)
+		}
+		for meetings := 1; meetings <= 3; meetings++ {
+			rounds := roundRobin(teams, meetings)
+			pairs := make(map[string]int)
+			home := make(map[string]int)
+			for _, round := range rounds {
+				playing := make(map[string]bool)
+				for _, m := range round {
+					assert.False(t, playing[m.team1] || playing[m.team2])
+					playing[m.team1], playing[m.team2] = true, true
+					pairs[m.team1+" v "+m.team2]++
+					home[m.team1]++
+					home[m.team2]--
+				}
+			}
+			for i, t1 := range teams {
+				for _, t2 := range teams[i+1:] {
+					assert.Equal(t, meetings, pairs[t1+" v "+t2]+pairs[t2+" v "+t1])
+				}
+			}
+			for _, team := range teams {
+				balance := home[team]
+				if n%2 == 1 || meetings%2 == 0 {
+					assert.Equal(t, 0, balance, "%d teams, %d meetings, team %s", n, meetings, team)
+				} else {
+					assert.True(t, balance == 1 || balance == -1)
+				}
+			}
+		}
+	}
+}
+
+func leagueTestData() string {
+	return `{
+		"year": 2019,
+		"divisions": [
+			{"name": "1", "teams": ["11", "12", "13", "14"]},
+			{"name": "2", "teams": ["21", "22", "23", "24", "25"]}
+		],
+		"dates": [
+			{"date": "1 Sep", "start": 6, "end": 7},
+			{"date": "8 Sep", "start": 6, "end": 7},
+			{"date": "15 Sep", "start": 6, "end": 7},
+			{"date": "22 Sep", "start": 6, "end": 7},
+			{"date": "29 Sep", "start": 6, "end": 7},
+			{"date": "6 Oct", "start": 6, "end": 7},
+			{"date": "13 Oct", "start": 6, "end": 7},
+			{"date": "20 Oct", "start": 6, "end": 7},
+			{"date": "27 Oct", "start": 6, "end": 7},
+			{"date": "3 Nov", "start": 6, "end": 7}
+		],
+		"meetings": 2
+	}`
+}
+
+func TestLeagueGenerate(t *testing.T) {
+	league, err := LoadLeague(strings.NewReader(leagueTestData()))
+	assert.Nil(t, err)
+	fl, err := league.Generate()
+	assert.Nil(t, err)
+	assert.False(t, Validate(fl).HasErrors(), Validate(fl).Errors())
+	matches := 0
+	divisions := make(map[string]int)
+	for _, w := range fl {
+		matches += len(w.matches)
+		assert.True(t, len(w.matches) <= 4)
+		for _, m := range w.matches {
+			divisions[w.date+" "+m.team1[:1]]++
+		}
+	}
+	assert.Equal(t, 2*6+2*10, matches)
+	assert.Equal(t, league.Divisions, fl.Divisions())
+	assert.Equal(t, 10, len(fl))
+	assert.Equal(t, time.Date(2019, time.September, 1, 0, 0, 0, 0, time.Local), fl[0].day)
+	for _, w := range fl {
+		assert.True(t, divisions[w.date+" 1"] > 0 || divisions[w.date+" 2"] > 0)
+	}
+	first, last := -1, -1
+	for i, w := range fl {
+		if divisions[w.date+" 1"] > 0 {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	assert.True(t, first <= 1 && last >= 8, "division 1 is played from week %d to %d", first+1, last+1)
+}
+
+func TestLeagueGenerateNotValid(t *testing.T) {
+	checker := func(data string, message string) {
+		league, err := LoadLeague(strings.NewReader(data))
+		assert.Nil(t, err)
+		fl, err := league.Generate()
+		assert.Nil(t, fl)
+		assert.EqualError(t, err, message)
+	}
+	dates := `"dates": [{"date": "1 Sep", "start": 6, "end": 7}, {"date": "8 Sep", "start": 6, "end": 7}]`
+	checker(`{"divisions": [{"name": "1", "teams": ["11", "12"]}], `+dates+`}`, "meetings must be at least 1, found 0")
+	checker(`{"divisions": [{"name": "1", "teams": ["11", "12"]}], "meetings": 1}`, "no dates available")
+	checker(`{"divisions": [{"name": "1", "teams": ["11", "12"]}], "dates": [{"date": "1/9", "start": 6, "end": 7}], "meetings": 1}`,
+		"date 1: date \"1/9\" is not in the form 30 Sep")
+	checker(`{"divisions": [{"name": "1", "teams": ["11"]}], `+dates+`, "meetings": 1}`,
+		"division 1 (1): expected at least 2 teams, found 1")
+	checker(`{"divisions": [{"name": "1", "teams": ["11", "12"]}, {"name": "2", "teams": ["21", "11"]}], `+dates+`, "meetings": 1}`,
+		"division 2 (2): team 11 is already in division 1")
+	checker(`{"divisions": [{"name": "1", "teams": ["11", "12", "13", "14"]}], `+dates+`, "meetings": 1}`,
+		"division 1: 3 rounds to play but only 2 dates")
+	single := `{"date": "1 Sep", "start": 6, "end": 6, "firstTimeSingle": true}`
+	checker(`{"divisions": [{"name": "1", "teams": ["11", "12", "13", "14"]}], `+
+		`"dates": [`+single+`, `+single+`, `+single+`], "meetings": 1}`,
+		"division 1: no date left for round 1 of 3")
+}
+
+func TestSessionCapacity(t *testing.T) {
+	assert.Equal(t, 8, (&Session{Slots: Slots{Start: 6, End: 9}}).capacity())
+	assert.Equal(t, 7, (&Session{Slots: Slots{Start: 6, End: 9}, FirstTimeSingle: true}).capacity())
+	assert.Equal(t, 10, (&Session{Slots: Slots{Start: 6, End: 9}, FirstTimeSingle: true, Courts: []string{"A", "B", "C"}}).capacity())
+	assert.Equal(t, 4, (&Session{Slots: Slots{Start: 6, End: 9}, FirstTimeSingle: true, Courts: []string{"A"}}).capacity())
+}
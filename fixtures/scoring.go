@@ -0,0 +1,366 @@
+package fixtures
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Scorer interface {
+	Score(ts *TeamSchedule) int
+	Bound(ts *TeamSchedule, remaining int) int
+}
+
+type Criterion struct {
+	Attribute string   `json:"attribute"`
+	Weight    int      `json:"weight"`
+	Values    []string `json:"values"`
+}
+
+// Cap limits how many matches a team plays in one timeslot, or in one of
+// the periods named by the scoring configuration.
+type Cap struct {
+	Timeslot *Time  `json:"timeslot,omitempty"`
+	Period   string `json:"period,omitempty"`
+	Max      int    `json:"max"`
+}
+
+func (c *Cap) when() string {
+	if c.Period != "" {
+		return "in the " + c.Period + " period"
+	}
+	return "at " + c.Timeslot.String()
+}
+
+// ScoringConfig lists the criteria a team's matches are balanced over. The
+// attribute of a criterion is "timeslot", "court", or "period" to group the
+// timeslots into the Periods given.
+//
+// UnavailablePenalty is added for each match a team plays when it is
+// unavailable; a configuration that is loaded without one has
+// DefaultUnavailablePenalty.
+type ScoringConfig struct {
+	Periods            []Period    `json:"periods,omitempty"`
+	Criteria           []Criterion `json:"criteria"`
+	Caps               []Cap       `json:"caps"`
+	CapPenalty         int         `json:"capPenalty"`
+	UnavailablePenalty int         `json:"unavailablePenalty"`
+}
+
+func DefaultScoringConfig() ScoringConfig {
+	return ScoringConfig{
+		Criteria: []Criterion{
+			{Attribute: "timeslot", Weight: 10, Values: []string{"18.15", "19.15", "20.15", "21.15"}},
+			{Attribute: "court", Weight: 1, Values: []string{"A", "B"}},
+		},
+		Caps: []Cap{
+			{Timeslot: At(17, 15).Ref(), Max: 1},
+			{Timeslot: At(21, 15).Ref(), Max: 2},
+		},
+		CapPenalty:         100,
+		UnavailablePenalty: DefaultUnavailablePenalty,
+	}
+}
+
+// WithCourts returns a copy of the configuration whose court criteria count
+// exactly the courts given, so that a team which never plays on one of the
+// season's courts is penalised for it.
+func (config ScoringConfig) WithCourts(courts []string) ScoringConfig {
+	answer := config
+	answer.Criteria = make([]Criterion, len(config.Criteria))
+	for i, c := range config.Criteria {
+		if c.Attribute == "court" {
+			c.Values = append([]string{}, courts...)
+		}
+		answer.Criteria[i] = c
+	}
+	return answer
+}
+
+// OnClock returns a copy of the configuration with its times, written on
+// a season's clock, on the 24-hour clock.
+func (config ScoringConfig) OnClock(c Clock) ScoringConfig {
+	answer := config
+	answer.Periods = append([]Period(nil), config.Periods...)
+	for i, p := range answer.Periods {
+		answer.Periods[i].From, answer.Periods[i].To = c.convert(p.From), c.convert(p.To)
+	}
+	answer.Criteria = append([]Criterion(nil), config.Criteria...)
+	for i, cr := range answer.Criteria {
+		if cr.Attribute != "timeslot" {
+			continue
+		}
+		answer.Criteria[i].Values = append([]string(nil), cr.Values...)
+		for j, v := range cr.Values {
+			if t, err := ParseTime(v); err == nil {
+				answer.Criteria[i].Values[j] = c.convert(t).String()
+			}
+		}
+	}
+	answer.Caps = append([]Cap(nil), config.Caps...)
+	for i, cp := range answer.Caps {
+		answer.Caps[i].Timeslot = c.convertRef(cp.Timeslot)
+	}
+	return answer
+}
+
+var DefaultScorer Scorer = &criteriaScorer{DefaultScoringConfig()}
+
+var attributeNames = []string{"timeslot", "court", "period"}
+
+func (config *ScoringConfig) attribute(name string) func(m *ScheduledMatch) string {
+	switch name {
+	case "timeslot":
+		return func(m *ScheduledMatch) string {
+			return m.timeslot.String()
+		}
+	case "court":
+		return func(m *ScheduledMatch) string {
+			return m.court
+		}
+	case "period":
+		return func(m *ScheduledMatch) string {
+			return config.period(m.timeslot)
+		}
+	}
+	return nil
+}
+
+// period returns the name of the first period containing the time, or ""
+// if there is none.
+func (config *ScoringConfig) period(t Time) string {
+	for i := range config.Periods {
+		if config.Periods[i].contains(t) {
+			return config.Periods[i].Name
+		}
+	}
+	return ""
+}
+
+func NewScorer(config ScoringConfig) (Scorer, error) {
+	periods := make(map[string]bool)
+	for i, p := range config.Periods {
+		if p.Name == "" || periods[p.Name] {
+			return nil, fmt.Errorf("period %d: name %q is missing or not unique", i+1, p.Name)
+		}
+		if p.To <= p.From {
+			return nil, fmt.Errorf("period %s: ends at %v, not after it starts at %v", p.Name, p.To, p.From)
+		}
+		periods[p.Name] = true
+	}
+	for i, c := range config.Criteria {
+		if config.attribute(c.Attribute) == nil {
+			return nil, fmt.Errorf("criterion %d: unknown attribute %q", i+1, c.Attribute)
+		}
+		for _, v := range c.Values {
+			if c.Attribute == "period" && !periods[v] {
+				return nil, fmt.Errorf("criterion %d: unknown period %q", i+1, v)
+			}
+			if c.Attribute == "timeslot" {
+				if t, err := ParseTime(v); err != nil || t.String() != v {
+					return nil, fmt.Errorf("criterion %d: timeslot %q would never match; write times like 6.15", i+1, v)
+				}
+			}
+		}
+		if c.Weight < 0 {
+			return nil, fmt.Errorf("criterion %d: weight %d is negative", i+1, c.Weight)
+		}
+	}
+	for i, c := range config.Caps {
+		if c.Max < 0 {
+			return nil, fmt.Errorf("cap %d: maximum %d is negative", i+1, c.Max)
+		}
+		if (c.Timeslot == nil) == (c.Period == "") {
+			return nil, fmt.Errorf("cap %d: expected either a timeslot or a period", i+1)
+		}
+		if c.Period != "" && !periods[c.Period] {
+			return nil, fmt.Errorf("cap %d: unknown period %q", i+1, c.Period)
+		}
+	}
+	if config.CapPenalty < 0 {
+		return nil, fmt.Errorf("cap penalty %d is negative", config.CapPenalty)
+	}
+	if config.UnavailablePenalty < 0 {
+		return nil, fmt.Errorf("unavailable penalty %d is negative", config.UnavailablePenalty)
+	}
+	return &criteriaScorer{config}, nil
+}
+
+func LoadScoringConfig(r io.Reader) (ScoringConfig, error) {
+	config := ScoringConfig{UnavailablePenalty: DefaultUnavailablePenalty}
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return ScoringConfig{}, err
+	}
+	// The original format gave timeslot values as the hour alone.
+	for _, c := range config.Criteria {
+		for i, v := range c.Values {
+			if hour, err := strconv.Atoi(v); err == nil && c.Attribute == "timeslot" {
+				c.Values[i] = At(hour, 15).String()
+			}
+		}
+	}
+	return config, nil
+}
+
+func ReadScoringConfig(filename string) (ScoringConfig, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return ScoringConfig{}, err
+	}
+	defer f.Close()
+	answer, err := LoadScoringConfig(f)
+	if err != nil {
+		return ScoringConfig{}, fmt.Errorf("%s: %v", filename, err)
+	}
+	return answer, nil
+}
+
+type criteriaScorer struct {
+	config ScoringConfig
+}
+
+func (cs *criteriaScorer) Score(ts *TeamSchedule) int {
+	return cs.Bound(ts, 0)
+}
+
+func (cs *criteriaScorer) Bound(ts *TeamSchedule, remaining int) int {
+	answer := 0
+	if len(cs.config.exceededCaps(ts)) > 0 {
+		answer = cs.config.CapPenalty
+	}
+	answer += cs.config.UnavailablePenalty * ts.unavailableCount()
+	for _, c := range cs.config.Criteria {
+		_, counts := c.counts(ts, cs.config.attribute(c.Attribute))
+		answer += c.Weight * imbalanceBound(counts, remaining)
+	}
+	return answer
+}
+
+func (config *ScoringConfig) exceededCaps(ts *TeamSchedule) []Cap {
+	if len(config.Caps) == 0 {
+		return nil
+	}
+	timeslots := make(map[Time]int)
+	periods := make(map[string]int)
+	for _, m := range ts.matches {
+		timeslots[m.timeslot]++
+		if len(config.Periods) > 0 {
+			periods[config.period(m.timeslot)]++
+		}
+	}
+	answer := make([]Cap, 0)
+	for _, c := range config.Caps {
+		if c.Period == "" && timeslots[*c.Timeslot] > c.Max || c.Period != "" && periods[c.Period] > c.Max {
+			answer = append(answer, c)
+		}
+	}
+	return answer
+}
+
+// counts returns how many of the team's matches have each value of the
+// attribute: the criterion's own values first, then any others in the
+// order they are played. Matches with no value, such as those outside
+// every period, are not counted.
+func (c *Criterion) counts(ts *TeamSchedule, attribute func(m *ScheduledMatch) string) ([]string, []int) {
+	indices := make(map[string]int, len(c.Values)+2)
+	values := append(make([]string, 0, len(c.Values)+2), c.Values...)
+	answer := make([]int, len(c.Values), len(c.Values)+2)
+	for i, v := range c.Values {
+		indices[v] = i
+	}
+	for _, m := range ts.matches {
+		v := attribute(m)
+		if v == "" {
+			continue
+		}
+		i, found := indices[v]
+		if !found {
+			i = len(answer)
+			indices[v] = i
+			values = append(values, v)
+			answer = append(answer, 0)
+		}
+		answer[i]++
+	}
+	return values, answer
+}
+
+// Score ranks a schedule by its worst team's score, then by the next worst
+// team's, and so on down to the best team's, so that schedules tied on
+// their worst team are told apart by how fair they are to everyone else.
+type Score []int
+
+func (s *Schedule) Score() Score {
+	return s.ScoreWith(DefaultScorer)
+}
+
+func (s *Schedule) ScoreWith(scorer Scorer) Score {
+	teams := s.teamSchedules()
+	answer := make(Score, len(teams))
+	for i, ts := range teams {
+		answer[i] = scorer.Score(ts)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(answer)))
+	return answer
+}
+
+func ParseScore(text string) (Score, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no score found")
+	}
+	answer := make(Score, len(fields))
+	for i, f := range fields {
+		v, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, err
+		}
+		answer[i] = v
+	}
+	return answer, nil
+}
+
+// Better reports whether s ranks ahead of other, which may be nil when
+// there is nothing to beat. A score with fewer levels, such as one read
+// from an old best file, loses any tie on the levels it has.
+func (s Score) Better(other Score) bool {
+	if other == nil {
+		return s != nil
+	}
+	for i := 0; i < len(s) && i < len(other); i++ {
+		if s[i] != other[i] {
+			return s[i] < other[i]
+		}
+	}
+	return len(s) > len(other)
+}
+
+// Worst returns the worst team's score, or -1 if there is no score.
+func (s Score) Worst() int {
+	if len(s) == 0 {
+		return -1
+	}
+	return s[0]
+}
+
+func (s Score) Total() int {
+	answer := 0
+	for _, v := range s {
+		answer += v
+	}
+	return answer
+}
+
+func (s Score) String() string {
+	levels := make([]string, len(s))
+	for i, v := range s {
+		levels[i] = strconv.Itoa(v)
+	}
+	return strings.Join(levels, " ")
+}
@@ -0,0 +1,238 @@
+package fixtures
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+// originalScore is the fixed formula used before scoring was configurable,
+// with the hours of the evening as the league writes them.
+func originalScore(ts *TeamSchedule) int {
+	timeslots := map[int]int{6: 0, 7: 0, 8: 0, 9: 0}
+	courts := map[string]int{"A": 0, "B": 0}
+	for _, m := range ts.matches {
+		timeslots[int(m.timeslot)/60-12]++
+		courts[m.court]++
+	}
+	answer := 0
+	if timeslots[5] > 1 || timeslots[9] > 2 {
+		answer = 100
+	}
+	spread := func(counts []int) int {
+		min, max := counts[0], counts[0]
+		for _, c := range counts {
+			if c < min {
+				min = c
+			}
+			if c > max {
+				max = c
+			}
+		}
+		return max - min
+	}
+	tcounts := make([]int, 0, len(timeslots))
+	for _, c := range timeslots {
+		tcounts = append(tcounts, c)
+	}
+	return answer + 10*spread(tcounts) + spread([]int{courts["A"], courts["B"]})
+}
+
+func TestDefaultScorerMatchesOriginalScore(t *testing.T) {
+	list := buildFixtureList(t)
+	it := list.Iterator(0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0)
+	for i := 0; i < 50; i++ {
+		s, ok := it.Next()
+		assert.True(t, ok)
+		for _, ts := range s.teamSchedules() {
+			assert.Equal(t, originalScore(ts), DefaultScorer.Score(ts))
+		}
+	}
+	a := NewAnnealer(list, DefaultScorer, 3)
+	for i := 0; i < 50; i++ {
+		a.Step()
+		current := a.current()
+		for _, ts := range current.teamSchedules() {
+			assert.Equal(t, originalScore(ts), DefaultScorer.Score(ts))
+		}
+	}
+}
+
+func TestCustomScorer(t *testing.T) {
+	list := buildFixtureList(t)
+	s, ok := list.Iterator().Next()
+	assert.True(t, ok)
+	courtsOnly, err := NewScorer(ScoringConfig{
+		Criteria: []Criterion{{Attribute: "court", Weight: 5, Values: []string{"A", "B"}}},
+	})
+	assert.Nil(t, err)
+	ts := s.teamSchedules()[0]
+	courts := map[string]int{}
+	for _, m := range ts.matches {
+		courts[m.court]++
+	}
+	expected := 5 * (courts["A"] - courts["B"])
+	if expected < 0 {
+		expected = -expected
+	}
+	assert.Equal(t, expected, courtsOnly.Score(ts))
+	assert.NotEqual(t, s.Evaluate(), s.EvaluateWith(courtsOnly))
+
+	nothing, err := NewScorer(ScoringConfig{})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, s.EvaluateWith(nothing))
+}
+
+func TestCapPenalty(t *testing.T) {
+	weeks := FixtureWeekList{
+		NewWeek("31 May", 6, 6, false, NewMatch("11", "12")),
+		NewWeek("1 Jun", 6, 6, false, NewMatch("11", "12")),
+	}
+	s, ok := weeks.Iterator().Next()
+	assert.True(t, ok)
+	capped, err := NewScorer(ScoringConfig{Caps: []Cap{{Timeslot: At(6, 15).Ref(), Max: 1}}, CapPenalty: 7})
+	assert.Nil(t, err)
+	assert.Equal(t, 7, s.EvaluateWith(capped))
+	relaxed, err := NewScorer(ScoringConfig{Caps: []Cap{{Timeslot: At(6, 15).Ref(), Max: 2}}, CapPenalty: 7})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, s.EvaluateWith(relaxed))
+}
+
+func TestPeriodScoring(t *testing.T) {
+	times := []Time{At(7, 0), At(7, 40), At(8, 20)}
+	weeks := FixtureWeekList{
+		NewWeekWithTimes("31 May", times, false, []string{"A"}, NewMatch("11", "12"), NewMatch("13", "14"), NewMatch("15", "16")),
+		NewWeekWithTimes("1 Jun", times, false, []string{"A"}, NewMatch("11", "13"), NewMatch("12", "15"), NewMatch("14", "16")),
+	}
+	s, ok := weeks.Iterator().Next()
+	assert.True(t, ok)
+	config := ScoringConfig{
+		Periods:    []Period{{Name: "early", From: At(7, 0), To: At(7, 30)}, {Name: "late", From: At(8, 0), To: At(9, 0)}},
+		Criteria:   []Criterion{{Attribute: "period", Weight: 3, Values: []string{"early", "late"}}},
+		Caps:       []Cap{{Period: "early", Max: 1}},
+		CapPenalty: 50,
+	}
+	scorer, err := NewScorer(config)
+	assert.Nil(t, err)
+	ts := s.teamSchedules()[0]
+	assert.Equal(t, "11", ts.team)
+	assert.Equal(t, 50+3*2, scorer.Score(ts))
+	assert.Equal(t, []Cap{{Period: "early", Max: 1}}, config.exceededCaps(ts))
+	values, counts := config.Criteria[0].counts(ts, config.attribute("period"))
+	assert.Equal(t, []string{"early", "late"}, values)
+	assert.Equal(t, []int{2, 0}, counts)
+	assert.Equal(t, "", config.period(At(7, 40)))
+}
+
+func TestNewScorerNotValid(t *testing.T) {
+	checker := func(config ScoringConfig, message string) {
+		scorer, err := NewScorer(config)
+		assert.Nil(t, scorer)
+		assert.EqualError(t, err, message)
+	}
+	checker(ScoringConfig{Criteria: []Criterion{{Attribute: "venue"}}}, "criterion 1: unknown attribute \"venue\"")
+	checker(ScoringConfig{Criteria: []Criterion{{Attribute: "court", Weight: -1}}}, "criterion 1: weight -1 is negative")
+	checker(ScoringConfig{Caps: []Cap{{Timeslot: At(5, 15).Ref(), Max: -2}}}, "cap 1: maximum -2 is negative")
+	checker(ScoringConfig{CapPenalty: -100}, "cap penalty -100 is negative")
+	checker(ScoringConfig{Caps: []Cap{{Max: 1}}}, "cap 1: expected either a timeslot or a period")
+	checker(ScoringConfig{Caps: []Cap{{Period: "early", Max: 1}}}, "cap 1: unknown period \"early\"")
+	checker(ScoringConfig{Periods: []Period{{Name: "late", From: At(9, 0), To: At(8, 0)}}}, "period late: ends at 8.00, not after it starts at 9.00")
+	checker(ScoringConfig{Periods: []Period{{Name: "late", From: At(8, 0), To: At(9, 0)}},
+		Criteria: []Criterion{{Attribute: "period", Values: []string{"early"}}}}, "criterion 1: unknown period \"early\"")
+	checker(ScoringConfig{Criteria: []Criterion{{Attribute: "timeslot", Values: []string{"6.15", "7:15"}}}}, "criterion 1: timeslot \"7:15\" would never match; write times like 6.15")
+	checker(ScoringConfig{Criteria: []Criterion{{Attribute: "timeslot", Values: []string{"early"}}}}, "criterion 1: timeslot \"early\" would never match; write times like 6.15")
+}
+
+func TestLoadScoringConfig(t *testing.T) {
+	config, err := LoadScoringConfig(strings.NewReader(`{
+		"criteria": [
+			{"attribute": "timeslot", "weight": 10, "values": ["6.15", "7.15", "8.15", "9.15"]},
+			{"attribute": "court", "weight": 1, "values": ["A", "B"]}
+		],
+		"caps": [{"timeslot": "5.15", "max": 1}, {"timeslot": "9.15", "max": 2}],
+		"capPenalty": 100
+	}`))
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultScoringConfig(), config.OnClock(EveningClock))
+
+	config, err = LoadScoringConfig(strings.NewReader(`{
+		"criteria": [
+			{"attribute": "timeslot", "weight": 10, "values": ["6", "7", "8", "9"]},
+			{"attribute": "court", "weight": 1, "values": ["A", "B"]}
+		],
+		"caps": [{"timeslot": 5, "max": 1}, {"timeslot": 9, "max": 2}],
+		"capPenalty": 100
+	}`))
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultScoringConfig(), config.OnClock(EveningClock))
+
+	config, err = LoadScoringConfig(strings.NewReader(`{"caps": [{"timeslot": "0.00", "max": 1}]}`))
+	assert.Nil(t, err)
+	assert.Equal(t, []Cap{{Timeslot: At(0, 0).Ref(), Max: 1}}, config.Caps)
+	_, err = NewScorer(config)
+	assert.Nil(t, err)
+
+	assert.Equal(t, DefaultScoringConfig(), DefaultScoringConfig().OnClock(EveningClock))
+	assert.Equal(t, config, config.OnClock(TwentyFourHourClock))
+
+	_, err = LoadScoringConfig(strings.NewReader(`{"criteria": [], "penalty": 5}`))
+	assert.EqualError(t, err, "json: unknown field \"penalty\"")
+}
+
+func TestScheduleScore(t *testing.T) {
+	list := buildFixtureList(t)
+	s, ok := list.Iterator().Next()
+	assert.True(t, ok)
+	score := s.Score()
+	assert.Equal(t, len(s.teamSchedules()), len(score))
+	assert.Equal(t, 164, score.Worst())
+	assert.Equal(t, s.Evaluate(), score.Worst())
+	for i := 1; i < len(score); i++ {
+		assert.True(t, score[i-1] >= score[i])
+	}
+}
+
+func TestScoreBetter(t *testing.T) {
+	assert.True(t, Score{10, 5}.Better(nil))
+	assert.False(t, Score(nil).Better(nil))
+	assert.True(t, Score{9, 9}.Better(Score{10, 0}))
+	assert.True(t, Score{10, 4}.Better(Score{10, 5}))
+	assert.False(t, Score{10, 5}.Better(Score{10, 5}))
+	assert.False(t, Score{10, 6}.Better(Score{10, 5}))
+	assert.True(t, Score{10, 5}.Better(Score{10}))
+	assert.False(t, Score{10}.Better(Score{10, 5}))
+	assert.False(t, Score{11, 0}.Better(Score{10}))
+}
+
+func TestParseScore(t *testing.T) {
+	score, err := ParseScore("164 150 20\n")
+	assert.Nil(t, err)
+	assert.Equal(t, Score{164, 150, 20}, score)
+	assert.Equal(t, "164 150 20", score.String())
+	assert.Equal(t, 334, score.Total())
+	assert.Equal(t, -1, Score(nil).Worst())
+	_, err = ParseScore("  ")
+	assert.EqualError(t, err, "no score found")
+	_, err = ParseScore("164 x")
+	assert.NotNil(t, err)
+}
+
+func TestScoringWithCourts(t *testing.T) {
+	courts := []string{"A", "B", "C"}
+	list := FixtureWeekList{
+		NewWeekWithCourts("31 May", 6, 6, false, courts, NewMatch("11", "12"), NewMatch("13", "14"), NewMatch("15", "16")),
+		NewWeekWithCourts("1 Jun", 6, 6, false, courts, NewMatch("11", "13"), NewMatch("12", "15"), NewMatch("14", "16")),
+	}
+	config := DefaultScoringConfig().WithCourts(list.Courts())
+	assert.Equal(t, courts, config.Criteria[1].Values)
+	assert.Equal(t, []string{"A", "B"}, DefaultScoringConfig().Criteria[1].Values)
+	scorer, err := NewScorer(config)
+	assert.Nil(t, err)
+	s := list.Combination(0, 0)
+	for _, ts := range s.teamSchedules() {
+		if ts.team == "11" {
+			// Two matches on court A and none on B or C.
+			assert.Equal(t, 20+2, scorer.Score(ts))
+		}
+	}
+}
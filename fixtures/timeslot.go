@@ -0,0 +1,186 @@
+package fixtures
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Time is a time of day in minutes after midnight, so that At(18, 15) is
+// the 6.15 evening slot.
+type Time int
+
+func At(hour int, minute int) Time {
+	return Time(hour*60 + minute)
+}
+
+// Clock is how a season's files write the hour of a time of day.
+type Clock string
+
+const (
+	// TwentyFourHourClock writes the 6.15 evening slot as 18.15, and is
+	// the clock of a season that does not give one.
+	TwentyFourHourClock Clock = "24-hour"
+	// EveningClock writes it as 6.15, as a league that plays only in the
+	// afternoon and evening does, so that an hour from 1 to 11 is after
+	// noon.
+	EveningClock Clock = "evening"
+)
+
+func (c Clock) check() error {
+	switch c {
+	case "", TwentyFourHourClock, EveningClock:
+		return nil
+	}
+	return fmt.Errorf("clock %q is not known: must be one of %s, %s", string(c), TwentyFourHourClock, EveningClock)
+}
+
+// convert returns a time written on the clock as a time on the 24-hour
+// clock. A time already on the 24-hour clock is unchanged, so that files
+// this package writes read back the same.
+func (c Clock) convert(t Time) Time {
+	if c == EveningClock && t >= At(1, 0) && t < At(12, 0) {
+		return t + At(12, 0)
+	}
+	return t
+}
+
+func (c Clock) convertAll(times []Time) []Time {
+	if times == nil {
+		return nil
+	}
+	answer := make([]Time, len(times))
+	for i, t := range times {
+		answer[i] = c.convert(t)
+	}
+	return answer
+}
+
+func (c Clock) convertRef(t *Time) *Time {
+	if t == nil {
+		return nil
+	}
+	return c.convert(*t).Ref()
+}
+
+// ParseTime reads a time written as the hour and minute, such as 18.15 or
+// 7:40, on the 24-hour clock unless it is converted from a season's.
+func ParseTime(s string) (Time, error) {
+	r, _ := regexp.Compile("^(\\d{1,2})[.:](\\d\\d)$")
+	groups := r.FindStringSubmatch(s)
+	if groups == nil {
+		return 0, fmt.Errorf("time %q is not in the form 6.15", s)
+	}
+	hour, _ := strconv.Atoi(groups[1])
+	minute, _ := strconv.Atoi(groups[2])
+	if hour > 23 || minute > 59 {
+		return 0, fmt.Errorf("time %q is out of range", s)
+	}
+	return At(hour, minute), nil
+}
+
+// Ref returns a pointer to the time, for the optional times of a Cap or an
+// Unavailability.
+func (t Time) Ref() *Time {
+	return &t
+}
+
+func (t Time) String() string {
+	return fmt.Sprintf("%d.%02d", int(t)/60, int(t)%60)
+}
+
+func (t Time) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON reads a time written as a string such as "6.15" or, as in
+// the original format, as the number of the hour whose quarter past it is.
+func (t *Time) UnmarshalJSON(data []byte) error {
+	var hour int
+	if err := json.Unmarshal(data, &hour); err == nil {
+		if hour < 0 || hour > 23 {
+			return fmt.Errorf("time %d is out of range", hour)
+		}
+		*t = At(hour, 15)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("time %s is not a string such as \"6.15\"", data)
+	}
+	parsed, err := ParseTime(s)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// DefaultDuration is how many minutes a match lasts unless the session
+// says otherwise.
+const DefaultDuration = 60
+
+// Slots describes when a session's timeslots start: at the Times listed;
+// or every Duration minutes, 60 if not given, from First to Last; or, in
+// the original format, at a quarter past each hour from Start to End.
+// Each match lasts Duration minutes.
+type Slots struct {
+	Start    int    `json:"start,omitempty"`
+	End      int    `json:"end,omitempty"`
+	First    Time   `json:"first,omitempty"`
+	Last     Time   `json:"last,omitempty"`
+	Duration int    `json:"duration,omitempty"`
+	Times    []Time `json:"times,omitempty"`
+}
+
+func (s *Slots) times() []Time {
+	if s.Times != nil {
+		return s.Times
+	}
+	answer := make([]Time, 0)
+	if s.First == 0 && s.Last == 0 {
+		for h := s.Start; h <= s.End; h++ {
+			answer = append(answer, At(h, 15))
+		}
+		return answer
+	}
+	for t := s.First; t <= s.Last; t += Time(s.duration()) {
+		answer = append(answer, t)
+	}
+	return answer
+}
+
+func (s *Slots) duration() int {
+	if s.Duration <= 0 {
+		return DefaultDuration
+	}
+	return s.Duration
+}
+
+// slotsOf describes the times given as simply as possible.
+func slotsOf(times []Time) Slots {
+	if len(times) == 0 {
+		return Slots{}
+	}
+	hourly := true
+	for i, t := range times {
+		hourly = hourly && int(t)%60 == 15 && (i == 0 || t == times[i-1]+60)
+	}
+	if hourly {
+		return Slots{Start: int(times[0]) / 60, End: int(times[len(times)-1]) / 60}
+	}
+	return Slots{Times: times}
+}
+
+// Period names a range of times, such as "early" or "late", from From up
+// to but not including To.
+type Period struct {
+	Name string `json:"name"`
+	From Time   `json:"from"`
+	To   Time   `json:"to"`
+}
+
+func (p *Period) contains(t Time) bool {
+	return p.From <= t && t < p.To
+}
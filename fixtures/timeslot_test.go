@@ -0,0 +1,73 @@
+package fixtures
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// quarterPast returns the timeslots at a quarter past each of the hours.
+func quarterPast(hours ...int) []Time {
+	answer := make([]Time, len(hours))
+	for i, h := range hours {
+		answer[i] = At(h, 15)
+	}
+	return answer
+}
+
+func TestParseTime(t *testing.T) {
+	for s, expected := range map[string]Time{"6.15": At(6, 15), "7:00": At(7, 0), "18:40": At(18, 40), "0.05": 5} {
+		parsed, err := ParseTime(s)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, parsed)
+	}
+	for _, s := range []string{"", "6", "6.5", "6.155", "24.00", "6.60", "six"} {
+		_, err := ParseTime(s)
+		assert.NotNil(t, err, s)
+	}
+	assert.Equal(t, "6.15", At(6, 15).String())
+	assert.Equal(t, "7.00", At(7, 0).String())
+	assert.Equal(t, "19.15", At(19, 15).String())
+}
+
+func TestClock(t *testing.T) {
+	for _, times := range [][3]int{{6, 15, 18}, {11, 59, 23}, {12, 30, 12}, {18, 40, 18}, {0, 5, 0}} {
+		assert.Equal(t, At(times[2], times[1]), EveningClock.convert(At(times[0], times[1])))
+		assert.Equal(t, At(times[0], times[1]), TwentyFourHourClock.convert(At(times[0], times[1])))
+		assert.Equal(t, At(times[0], times[1]), Clock("").convert(At(times[0], times[1])))
+	}
+	assert.Nil(t, EveningClock.check())
+	assert.EqualError(t, Clock("12-hour").check(), "clock \"12-hour\" is not known: must be one of 24-hour, evening")
+}
+
+func TestTimeJSON(t *testing.T) {
+	var times []Time
+	assert.Nil(t, json.Unmarshal([]byte(`["6.15", "7:40"]`), &times))
+	assert.Equal(t, []Time{At(6, 15), At(7, 40)}, times)
+	data, err := json.Marshal(times)
+	assert.Nil(t, err)
+	assert.Equal(t, `["6.15","7.40"]`, string(data))
+	assert.Nil(t, json.Unmarshal([]byte(`[6, "0.00"]`), &times))
+	assert.Equal(t, []Time{At(6, 15), 0}, times)
+	assert.EqualError(t, json.Unmarshal([]byte(`[24]`), &times), "time 24 is out of range")
+	assert.EqualError(t, json.Unmarshal([]byte(`[6.5]`), &times), "time 6.5 is not a string such as \"6.15\"")
+}
+
+func TestSlotsTimes(t *testing.T) {
+	assert.Equal(t, quarterPast(6, 7, 8, 9), (&Slots{Start: 6, End: 9}).times())
+	assert.Equal(t, []Time{At(7, 0), At(7, 40), At(8, 20), At(9, 0)},
+		(&Slots{First: At(7, 0), Last: At(9, 0), Duration: 40}).times())
+	assert.Equal(t, []Time{At(7, 0), At(8, 0)}, (&Slots{First: At(7, 0), Last: At(8, 30)}).times())
+	assert.Equal(t, []Time{At(7, 0), At(7, 45)}, (&Slots{Start: 6, Times: []Time{At(7, 0), At(7, 45)}}).times())
+	assert.Equal(t, Slots{Start: 6, End: 8}, slotsOf(quarterPast(6, 7, 8)))
+	assert.Equal(t, Slots{Times: quarterPast(6, 8)}, slotsOf(quarterPast(6, 8)))
+	assert.Equal(t, Slots{Times: []Time{At(7, 0), At(7, 40)}}, slotsOf([]Time{At(7, 0), At(7, 40)}))
+}
+
+func TestNewWeekWithTimes(t *testing.T) {
+	w := NewWeekWithTimes("31 May", []Time{At(7, 0), At(7, 40), At(8, 20)}, true, DefaultCourts,
+		NewMatch("11", "12"), NewMatch("13", "14"), NewMatch("15", "16"), NewMatch("17", "18"))
+	assert.Equal(t, []Time{At(7, 0), At(7, 40), At(7, 40), At(8, 20)}, w.timeslots)
+	s := w.combination(0)
+	assert.Equal(t, "31 May, 7.40, B: 15 v 16\n", s[2].String())
+}
@@ -0,0 +1,148 @@
+package fixtures
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// DefaultUnavailablePenalty is the penalty for each match a team is
+// scheduled to play when it has said it is unavailable, unless the scoring
+// configuration gives another.
+const DefaultUnavailablePenalty = 1000
+
+// Unavailability records when a team cannot play: on one date, or on every
+// date if Date is empty; and then at any time, or only before the timeslot
+// Before and in the Timeslots listed.
+type Unavailability struct {
+	Team      string `json:"team"`
+	Date      string `json:"date,omitempty"`
+	Before    *Time  `json:"before,omitempty"`
+	Timeslots []Time `json:"timeslots,omitempty"`
+}
+
+func (u *Unavailability) excludes(team string, date string, timeslot Time) bool {
+	if u.Team != team || u.Date != "" && u.Date != date {
+		return false
+	}
+	if u.wholeDate() || u.Before != nil && timeslot < *u.Before {
+		return true
+	}
+	for _, t := range u.Timeslots {
+		if t == timeslot {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *Unavailability) wholeDate() bool {
+	return u.Before == nil && len(u.Timeslots) == 0
+}
+
+func (u *Unavailability) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString(fmt.Sprintf("team %s is unavailable", u.Team))
+	if u.Date != "" {
+		buffer.WriteString(fmt.Sprintf(" on %s", u.Date))
+	}
+	if u.Before != nil {
+		buffer.WriteString(fmt.Sprintf(" before %v", *u.Before))
+	}
+	for i, t := range u.Timeslots {
+		if i == 0 && u.Before != nil {
+			buffer.WriteString(" or")
+		}
+		if i == 0 {
+			buffer.WriteString(" at")
+		} else {
+			buffer.WriteString(",")
+		}
+		buffer.WriteString(fmt.Sprintf(" %v", t))
+	}
+	return buffer.String()
+}
+
+type Unavailabilities []Unavailability
+
+func (us Unavailabilities) excludes(team string, date string, timeslot Time) bool {
+	for i := range us {
+		if us[i].excludes(team, date, timeslot) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithUnavailability returns a copy of the fixture list in which each week
+// knows which of its teams are unavailable, and when. Each week lists the
+// unavailabilities for every date before those for its own, and the list
+// keeps the rest, such as those for a date with no week, so that they can
+// be validated and written.
+func (fl FixtureWeekList) WithUnavailability(us Unavailabilities) FixtureWeekList {
+	answer := make(FixtureWeekList, len(fl))
+	for i, w := range fl {
+		week := *w
+		week.unavailable = nil
+		week.seasonUnavailable = us
+		for _, date := range []string{"", w.date} {
+			for _, u := range us {
+				if u.Date == date {
+					week.unavailable = append(week.unavailable, u)
+				}
+			}
+		}
+		answer[i] = &week
+	}
+	return answer
+}
+
+// Unavailability returns every unavailability given to the fixture list.
+func (fl FixtureWeekList) Unavailability() Unavailabilities {
+	answer := make(Unavailabilities, 0)
+	seen := make(map[string]bool)
+	for _, w := range fl {
+		for _, u := range w.seasonUnavailable {
+			key := u.String()
+			if !seen[key] {
+				seen[key] = true
+				answer = append(answer, u)
+			}
+		}
+	}
+	return answer
+}
+
+// schedule places the match at the timeslot and court given, on the
+// week's day, noting which of its teams are unavailable then and the
+// division of its home team.
+func (w *Week) schedule(m *Match, timeslot Time, court string) *ScheduledMatch {
+	answer := NewScheduledMatch(m, w.date, timeslot, court)
+	answer.day, answer.duration, answer.division = w.day, w.duration, w.divisionOf[m.team1]
+	answer.unavailable = [2]bool{
+		w.unavailable.excludes(m.team1, w.date, timeslot),
+		w.unavailable.excludes(m.team2, w.date, timeslot),
+	}
+	return answer
+}
+
+func (ts *TeamSchedule) unavailableCount() int {
+	answer := 0
+	for _, m := range ts.matches {
+		if m.team1 == ts.team && m.unavailable[0] || m.team2 == ts.team && m.unavailable[1] {
+			answer++
+		}
+	}
+	return answer
+}
+
+func (w *Week) canPlay(team string) bool {
+	if len(w.timeslots) == 0 {
+		return true
+	}
+	for _, t := range w.timeslots {
+		if !w.unavailable.excludes(team, w.date, t) {
+			return true
+		}
+	}
+	return false
+}
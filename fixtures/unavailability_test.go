@@ -0,0 +1,142 @@
+package fixtures
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func unavailabilityTestList() FixtureWeekList {
+	return FixtureWeekList{
+		NewWeek("31 May", 6, 7, false,
+			NewMatch("11", "12"),
+			NewMatch("13", "14"),
+			NewMatch("15", "16")),
+		NewWeek("1 Jun", 6, 7, false,
+			NewMatch("11", "13"),
+			NewMatch("12", "15"),
+			NewMatch("14", "16")),
+	}
+}
+
+func TestUnavailabilityExcludes(t *testing.T) {
+	date := Unavailability{Team: "11", Date: "31 May"}
+	assert.True(t, date.excludes("11", "31 May", At(9, 15)))
+	assert.False(t, date.excludes("11", "1 Jun", At(9, 15)))
+	assert.False(t, date.excludes("12", "31 May", At(9, 15)))
+	early := Unavailability{Team: "11", Before: At(7, 15).Ref()}
+	assert.True(t, early.excludes("11", "31 May", At(6, 15)))
+	assert.False(t, early.excludes("11", "1 Jun", At(7, 15)))
+	slots := Unavailability{Team: "11", Date: "1 Jun", Before: At(6, 15).Ref(), Timeslots: quarterPast(8, 9)}
+	assert.True(t, slots.excludes("11", "1 Jun", At(5, 15)))
+	assert.True(t, slots.excludes("11", "1 Jun", At(9, 15)))
+	assert.False(t, slots.excludes("11", "1 Jun", At(7, 15)))
+	assert.Equal(t, "team 11 is unavailable on 31 May", date.String())
+	assert.Equal(t, "team 11 is unavailable before 7.15", early.String())
+	assert.Equal(t, "team 11 is unavailable on 1 Jun before 6.15 or at 8.15, 9.15", slots.String())
+	midnight := Unavailability{Team: "11", Before: At(0, 0).Ref()}
+	assert.False(t, midnight.excludes("11", "31 May", At(0, 0)))
+	assert.Equal(t, "team 11 is unavailable before 0.00", midnight.String())
+}
+
+func TestUnavailabilityPenalty(t *testing.T) {
+	list := unavailabilityTestList().WithUnavailability(Unavailabilities{{Team: "11", Before: At(7, 15).Ref()}})
+	s, ok := list.Iterator().Next()
+	assert.True(t, ok)
+	assert.Equal(t, "31 May, 6.15, A: 11 v 12\n", s[0].String())
+	assert.Equal(t, [2]bool{true, false}, s[0].unavailable)
+	assert.True(t, s.Evaluate() >= 2*DefaultUnavailablePenalty)
+	for _, ts := range s.teamSchedules() {
+		if ts.team == "11" {
+			assert.Equal(t, 2, ts.unavailableCount())
+		} else {
+			assert.Equal(t, 0, ts.unavailableCount())
+		}
+	}
+	r := s.Report(DefaultScoringConfig())
+	assert.Equal(t, "11", r[0].Team)
+	assert.Equal(t, 2, r[0].Unavailable)
+	assert.Contains(t, r[0].String(), "  2 matches when unavailable, penalty 2000\n")
+
+	best := s.Evaluate()
+	it := list.Iterator()
+	for s, ok := it.Next(); ok; s, ok = it.Next() {
+		if score := s.Evaluate(); score < best {
+			best = score
+		}
+	}
+	assert.True(t, best < DefaultUnavailablePenalty)
+}
+
+func TestAnnealerKeepsUnavailability(t *testing.T) {
+	list := unavailabilityTestList().WithUnavailability(Unavailabilities{{Team: "11", Timeslots: quarterPast(6)}})
+	a := NewAnnealer(list, DefaultScorer, 5)
+	for i := 0; i < 200; i++ {
+		a.Step()
+	}
+	best, score := a.Best()
+	assert.True(t, score < DefaultUnavailablePenalty)
+	for _, m := range best {
+		assert.Equal(t, m.timeslot == At(6, 15) && m.team1 == "11", m.unavailable[0])
+		assert.Equal(t, m.timeslot == At(6, 15) && m.team2 == "11", m.unavailable[1])
+	}
+}
+
+func TestValidateUnavailability(t *testing.T) {
+	list := unavailabilityTestList().WithUnavailability(Unavailabilities{
+		{Team: "11", Date: "31 May"},
+		{Team: "12", Date: "1 Jun", Timeslots: quarterPast(6, 7)},
+		{Team: "13", Date: "2 Jun"},
+		{Team: "99", Before: At(9, 15).Ref()},
+	})
+	assert.Equal(t, []string{
+		"error: week 1 (31 May): team 11 plays 11 v 12 but is unavailable at every timeslot",
+		"error: week 2 (1 Jun): team 12 plays 12 v 15 but is unavailable at every timeslot",
+		"warning: team 13 is unavailable on 2 Jun, but has no matches that day",
+		"warning: team 99 is unavailable before 9.15, but has no matches",
+	}, problemStrings(Validate(list)))
+	var buffer bytes.Buffer
+	assert.Nil(t, WriteFixtureList(&buffer, list))
+	assert.Contains(t, buffer.String(), `"date": "2 Jun"`)
+}
+
+func TestUnavailablePenaltyIsConfigurable(t *testing.T) {
+	list := unavailabilityTestList().WithUnavailability(Unavailabilities{{Team: "11"}})
+	s := list.Combination(0, 0)
+	config, err := LoadScoringConfig(strings.NewReader(`{"criteria": [], "caps": []}`))
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultUnavailablePenalty, config.UnavailablePenalty)
+	config.UnavailablePenalty = 3
+	scorer, err := NewScorer(config)
+	assert.Nil(t, err)
+	assert.Equal(t, 6, s.EvaluateWith(scorer))
+	r := s.Report(config)
+	assert.Equal(t, 6, r[0].Score)
+	assert.Contains(t, r[0].String(), "  2 matches when unavailable, penalty 6\n")
+	config.UnavailablePenalty = -1
+	_, err = NewScorer(config)
+	assert.EqualError(t, err, "unavailable penalty -1 is negative")
+}
+
+func TestLoadFixtureListWithUnavailability(t *testing.T) {
+	data := `{"year": 2020, "weeks": [
+		{"date": "31 May", "start": 6, "end": 7, "matches": [["11", "12"], ["13", "14"]]},
+		{"date": "1 Jun", "start": 6, "end": 7, "matches": [["11", "13"], ["12", "14"]]}
+	], "unavailable": [
+		{"team": "11", "date": "1 Jun", "before": "7.15"},
+		{"team": "14", "timeslots": ["6.15"]}
+	]}`
+	fl, err := LoadFixtureList(strings.NewReader(data))
+	assert.Nil(t, err)
+	assert.Equal(t, Unavailabilities{{Team: "14", Timeslots: quarterPast(6)}}, fl[0].unavailable)
+	assert.Equal(t, Unavailabilities{{Team: "14", Timeslots: quarterPast(6)}, {Team: "11", Date: "1 Jun", Before: At(7, 15).Ref()}}, fl[1].unavailable)
+	var buffer bytes.Buffer
+	assert.Nil(t, WriteFixtureList(&buffer, fl))
+	loaded, err := LoadFixtureList(&buffer)
+	assert.Nil(t, err)
+	assert.Equal(t, fl, loaded)
+
+	_, err = LoadFixtureList(strings.NewReader(`{"year": 2020, "weeks": [], "unavailable": [{"date": "1 Jun"}]}`))
+	assert.EqualError(t, err, "unavailability 1 has no team")
+}
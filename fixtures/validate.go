@@ -0,0 +1,209 @@
+package fixtures
+
+import (
+	"fmt"
+)
+
+type Severity int
+
+const (
+	Warning Severity = iota
+	Error
+)
+
+func (s Severity) String() string {
+	if s == Error {
+		return "error"
+	}
+	return "warning"
+}
+
+type Problem struct {
+	Severity Severity
+	Week     int
+	Date     string
+	Message  string
+}
+
+func (p Problem) String() string {
+	if p.Week < 0 {
+		return fmt.Sprintf("%v: %s", p.Severity, p.Message)
+	}
+	return fmt.Sprintf("%v: week %d (%s): %s", p.Severity, p.Week+1, p.Date, p.Message)
+}
+
+type Problems []Problem
+
+func (ps Problems) HasErrors() bool {
+	return len(ps.Errors()) > 0
+}
+
+func (ps Problems) Errors() Problems {
+	return ps.filter(Error)
+}
+
+func (ps Problems) Warnings() Problems {
+	return ps.filter(Warning)
+}
+
+func (ps Problems) filter(severity Severity) Problems {
+	answer := make(Problems, 0, len(ps))
+	for _, p := range ps {
+		if p.Severity == severity {
+			answer = append(answer, p)
+		}
+	}
+	return answer
+}
+
+func Validate(fl FixtureWeekList) Problems {
+	answer := make(Problems, 0)
+	if len(fl) == 0 {
+		return append(answer, Problem{Error, -1, "", "the fixture list contains no weeks"})
+	}
+	if fl[0].guessedYear {
+		year := fl[0].day.Year()
+		answer = append(answer, Problem{Warning, -1, "", fmt.Sprintf("the season has no year, so it is taken to start in %d; add \"year\": %d to the season file", year, year)})
+	}
+	dates := make(map[string]int)
+	pairings := make(map[Match]int)
+	for wi, w := range fl {
+		report := func(severity Severity, format string, args ...interface{}) {
+			answer = append(answer, Problem{severity, wi, w.date, fmt.Sprintf(format, args...)})
+		}
+		if w.date == "" {
+			report(Error, "the week has no date")
+		} else if previous, found := dates[w.date]; found {
+			report(Error, "the date is also used by week %d", previous+1)
+		} else {
+			dates[w.date] = wi
+			if wi > 0 && !w.day.IsZero() && !w.day.After(fl[wi-1].day) {
+				report(Error, "the date is not after that of week %d (%s)", wi, fl[wi-1].date)
+			}
+		}
+		switch len(w.matches) {
+		case 0:
+			report(Error, "the week contains no matches")
+			continue
+		case 1:
+			report(Warning, "the week contains only one match, so there is nothing to arrange")
+		}
+		courts := make(map[string]bool)
+		for _, c := range w.courts {
+			if courts[c] {
+				report(Error, "court %q is listed more than once", c)
+			}
+			courts[c] = true
+		}
+		if len(w.courts) == 0 {
+			report(Error, "the week has no courts")
+		} else if len(w.timeslots) < len(w.matches) {
+			report(Error, "only %d timeslots are available for %d matches", len(w.timeslots), len(w.matches))
+		}
+		teamsThisWeek := make(map[string]bool)
+		for _, m := range w.matches {
+			if m.team1 == "" || m.team2 == "" {
+				report(Error, "match %q v %q has a missing team", m.team1, m.team2)
+				continue
+			}
+			if m.team1 == m.team2 {
+				report(Error, "team %s is drawn to play itself", m.team1)
+				continue
+			}
+			for _, t := range []string{m.team1, m.team2} {
+				if teamsThisWeek[t] {
+					report(Error, "team %s plays more than once", t)
+				}
+				teamsThisWeek[t] = true
+			}
+			if previous, found := pairings[*m]; found {
+				report(Warning, "%s v %s is also played in week %d (%s)", m.team1, m.team2, previous+1, fl[previous].date)
+			}
+			pairings[*m] = wi
+			for _, t := range []string{m.team1, m.team2} {
+				if !w.canPlay(t) {
+					report(Error, "team %s plays %s v %s but is unavailable at every timeslot", t, m.team1, m.team2)
+				}
+			}
+		}
+	}
+	teams := make(map[string]bool)
+	for _, w := range fl {
+		for _, m := range w.matches {
+			teams[m.team1], teams[m.team2] = true, true
+		}
+	}
+	for _, u := range fl.Unavailability() {
+		if !teams[u.Team] {
+			answer = append(answer, Problem{Warning, -1, "", fmt.Sprintf("%v, but has no matches", &u)})
+		} else if _, found := dates[u.Date]; u.Date != "" && !found {
+			answer = append(answer, Problem{Warning, -1, "", fmt.Sprintf("%v, but has no matches that day", &u)})
+		}
+	}
+	return answer
+}
+
+type slot struct {
+	timeslot Time
+	court    string
+}
+
+// ValidateSchedule checks that the schedule, such as one edited by hand,
+// arranges the fixture list: that each week's matches are all played
+// once, on its date and in its timeslots, and that no two share a
+// timeslot on the same court. The schedule's times are read on the
+// season's clock.
+func ValidateSchedule(fl FixtureWeekList, s Schedule) Problems {
+	answer := make(Problems, 0)
+	weeks := make(map[string]int, len(fl))
+	fixtures := make([]map[Match]bool, len(fl))
+	slots := make([]map[slot]bool, len(fl))
+	for wi, w := range fl {
+		weeks[w.date] = wi
+		fixtures[wi] = make(map[Match]bool, len(w.matches))
+		for _, m := range w.matches {
+			fixtures[wi][*m] = true
+		}
+		slots[wi] = make(map[slot]bool, len(w.timeslots))
+		for i, t := range w.timeslots {
+			slots[wi][slot{t, w.court(i)}] = true
+		}
+	}
+	played := make([]map[Match]bool, len(fl))
+	used := make([]map[slot]bool, len(fl))
+	for _, m := range s {
+		date, err := normaliseDate(m.date)
+		wi, found := weeks[date]
+		if err != nil || !found {
+			answer = append(answer, Problem{Error, -1, "", fmt.Sprintf("%s v %s is played on %s, which is not the date of any week", m.team1, m.team2, m.date)})
+			continue
+		}
+		report := func(format string, args ...interface{}) {
+			answer = append(answer, Problem{Error, wi, fl[wi].date, fmt.Sprintf(format, args...)})
+		}
+		if played[wi] == nil {
+			played[wi], used[wi] = make(map[Match]bool), make(map[slot]bool)
+		}
+		if !fixtures[wi][m.Match] {
+			report("%s v %s is not one of the week's matches", m.team1, m.team2)
+		} else if played[wi][m.Match] {
+			report("%s v %s is played more than once", m.team1, m.team2)
+		}
+		played[wi][m.Match] = true
+		sl := slot{fl[wi].clock.convert(m.timeslot), m.court}
+		if !slots[wi][sl] {
+			report("%s v %s is played at %v on court %s, which is not one of the week's timeslots", m.team1, m.team2, sl.timeslot, m.court)
+		} else if used[wi][sl] {
+			report("more than one match is played at %v on court %s", sl.timeslot, m.court)
+		}
+		used[wi][sl] = true
+	}
+	for wi, w := range fl {
+		for _, m := range w.matches {
+			if !played[wi][*m] {
+				answer = append(answer, Problem{Error, wi, w.date, fmt.Sprintf("%s v %s is not played", m.team1, m.team2)})
+			}
+		}
+	}
+	return answer
+}
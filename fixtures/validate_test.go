@@ -0,0 +1,101 @@
+package fixtures
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestValidateBuildFixtureList(t *testing.T) {
+	problems := Validate(buildFixtureList(t))
+	assert.False(t, problems.HasErrors())
+	assert.Equal(t, len(problems), len(problems.Warnings()))
+}
+
+func TestValidateEmptyList(t *testing.T) {
+	problems := Validate(FixtureWeekList{})
+	assert.True(t, problems.HasErrors())
+	assert.Equal(t, "error: the fixture list contains no weeks", problems[0].String())
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	list := FixtureWeekList{
+		NewWeek("31 May", 1, 2, true,
+			NewMatch("11", "12"),
+			NewMatch("13", "11"),
+			NewMatch("15", "15")),
+		NewWeek("1 Jun", 3, 3, false,
+			NewMatch("21", "22"),
+			NewMatch("23", "24"),
+			NewMatch("25", "26")),
+		NewWeek("2 Jun", 5, 6, false,
+			NewMatch("31", "32")),
+		NewWeek("1 Jun", 5, 6, false),
+		NewWeek("9 Jun", 1, 2, false,
+			NewMatch("11", "12"),
+			NewMatch("", "14")),
+	}
+	problems := Validate(list)
+	assert.Equal(t, []string{
+		"error: week 1 (31 May): team 11 plays more than once",
+		"error: week 1 (31 May): team 15 is drawn to play itself",
+		"error: week 2 (1 Jun): only 2 timeslots are available for 3 matches",
+		"warning: week 3 (2 Jun): the week contains only one match, so there is nothing to arrange",
+		"error: week 4 (1 Jun): the date is also used by week 2",
+		"error: week 4 (1 Jun): the week contains no matches",
+		"warning: week 5 (9 Jun): 11 v 12 is also played in week 1 (31 May)",
+		"error: week 5 (9 Jun): match \"\" v \"14\" has a missing team",
+	}, problemStrings(problems))
+	assert.Equal(t, 6, len(problems.Errors()))
+	assert.Equal(t, 2, len(problems.Warnings()))
+}
+
+func problemStrings(problems Problems) []string {
+	answer := make([]string, len(problems))
+	for i, p := range problems {
+		answer[i] = p.String()
+	}
+	return answer
+}
+
+func TestValidateCourts(t *testing.T) {
+	list := FixtureWeekList{
+		NewWeekWithCourts("31 May", 6, 9, false, []string{"A", "A"}, NewMatch("11", "12"), NewMatch("13", "14")),
+		NewWeekWithCourts("1 Jun", 6, 9, false, []string{}, NewMatch("11", "13"), NewMatch("12", "14")),
+	}
+	assert.Equal(t, []string{
+		"error: week 1 (31 May): court \"A\" is listed more than once",
+		"error: week 2 (1 Jun): the week has no courts",
+	}, problemStrings(Validate(list)))
+}
+
+func TestValidateSchedule(t *testing.T) {
+	list := unavailabilityTestList()
+	s := list.Combination(0, 0)
+	assert.Empty(t, ValidateSchedule(list, s))
+	loaded, err := LoadSchedule(strings.NewReader(s.String()))
+	assert.Nil(t, err)
+	assert.Empty(t, ValidateSchedule(list, loaded))
+}
+
+func TestValidateScheduleReportsEveryProblem(t *testing.T) {
+	list := unavailabilityTestList()
+	s, err := LoadSchedule(strings.NewReader(
+		"31 May, 6.15, A: 11 v 12\n" +
+			"31 May, 6.15, A: 13 v 14\n" +
+			"31 May, 7.15, A: 11 v 12\n" +
+			"1 Jun, 6.15, C: 11 v 13\n" +
+			"1 Jun, 7.15, A: 14 v 12\n" +
+			"3 Jun, 6.15, A: 11 v 14\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"error: week 1 (31 May): more than one match is played at 6.15 on court A",
+		"error: week 1 (31 May): 11 v 12 is played more than once",
+		"error: week 2 (1 Jun): 11 v 13 is played at 6.15 on court C, which is not one of the week's timeslots",
+		"error: week 2 (1 Jun): 14 v 12 is not one of the week's matches",
+		"error: 11 v 14 is played on 3 Jun, which is not the date of any week",
+		"error: week 1 (31 May): 15 v 16 is not played",
+		"error: week 2 (1 Jun): 12 v 15 is not played",
+		"error: week 2 (1 Jun): 14 v 16 is not played",
+	}, problemStrings(ValidateSchedule(list, s)))
+}
@@ -2,126 +2,486 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"fixtures/fixtures"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"regexp"
 	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"log"
-	"os/exec"
+	"math/big"
+	"sync/atomic"
+	"time"
 )
 
-const breakpointFile = "breakpoint"
-const bestFile = "best"
+var breakpointFile = "breakpoint"
+var bestFile = "best"
+var populationFile = "population"
+var reportFile = "report"
 
-var bestScore = -1
-const messageFrequency = 100000
-const commitFrequency = 1000000
+var bestScore atomic.Value
+var scoringConfig = fixtures.DefaultScoringConfig()
+var scorer = fixtures.DefaultScorer
 
 func main() {
-	bestScore = readBestScore()
-	resultChan := make(chan EvaluationResult, 10)
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGKILL)
-	stoppingChan := make(chan struct{}, 1)
-	wg := sync.WaitGroup{}
-	wg.Add(2)
-	go waitForSignal(sigChan, stoppingChan)
-	go processResults(resultChan, wg)
-	go processCombinations(resultChan, stoppingChan, wg)
-	wg.Wait()
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	c := findCommand(os.Args[1])
+	if c == nil {
+		log.Printf("Unknown command %q", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	fs := c.flagSet()
+	fs.Parse(os.Args[2:])
+	if fs.NArg() < c.minArgs || c.maxArgs >= 0 && fs.NArg() > c.maxArgs {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if err := setOutputDir(outputDir); err != nil {
+		log.Fatalf("Output directory %s could not be created: %v", outputDir, err)
+	}
+	readScoring()
+	c.run(fs.Args())
 }
 
-func waitForSignal(sigChan chan os.Signal, stoppingChan chan struct{}) {
-	for sig := range sigChan {
-		log.Printf("Signal %v received, stopping", sig)
-		stoppingChan <- struct{}{}
-		break
+// setOutputDir places the best, report, breakpoint and population files in
+// the directory given, so that each season can be searched in its own.
+func setOutputDir(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
 	}
+	for _, f := range []*string{&breakpointFile, &bestFile, &populationFile, &reportFile} {
+		*f = filepath.Join(dir, filepath.Base(*f))
+	}
+	return nil
 }
 
-func processCombinations(resultChan chan EvaluationResult, stoppingChan chan struct{}, wg sync.WaitGroup) {
-	defer wg.Done()
+func search(args []string) {
+	if populationInterval < 1 {
+		log.Fatalf("Population interval %d is not positive", populationInterval)
+	}
+	e, err := fixtures.ParseEnumeration(enumeration)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Enumerating each week's %v", e)
+	list := readFixtureList(seasonFile).WithEnumeration(e)
+	if seasonHash, err = hashFile(seasonFile); err != nil {
+		log.Fatalf("File %s could not be read: %v", seasonFile, err)
+	}
+	fitScoring(list)
+	if err := e.CheckScoring(scoringConfig); err != nil {
+		log.Fatal(err)
+	}
+	setBestScore(readBestScore())
+	if snapshotDir == "" {
+		snapshotDir = filepath.Join(outputDir, "snapshots")
+	}
+	if checkpointSink, err = newCheckpointSink(sinkName, snapshotDir, snapshotCount); err != nil {
+		log.Fatal(err)
+	}
+	if mode == "milp" {
+		solveModel(list)
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go waitForSignal(sigChan, cancel)
+	reportChan := make(chan os.Signal, 1)
+	if len(reportSignals) > 0 {
+		signal.Notify(reportChan, reportSignals...)
+	}
+	resultChan := make(chan EvaluationResult, 10)
+	var positions []breakpoint
+	switch mode {
+	case "exhaustive":
+		iterators := splitIterators(readBreakpoints(list), workers)
+		positions = make([]breakpoint, len(iterators))
+		for i, it := range iterators {
+			positions[i] = newBreakpoint(it)
+		}
+		active := workers
+		if len(iterators) < active {
+			active = len(iterators)
+		}
+		log.Printf("Searching %d ranges with %d workers", len(iterators), active)
+		go processCombinations(ctx, iterators, workers, resultChan)
+	case "anneal":
+		go anneal(ctx, list, resultChan)
+	case "genetic":
+		go evolve(ctx, list, resultChan)
+	default:
+		log.Fatalf("Unknown search mode %q", mode)
+	}
+	p := newProgress(list, positions)
+	var controlChan chan controlRequest
+	if httpAddress != "" {
+		c := newController(cancel, list)
+		defer close(c.finished)
+		controlChan = c.requests
+		go c.serve(httpAddress)
+	}
+	processResults(resultChan, positions, p, reportChan, controlChan)
+	outcome := "finished"
+	if ctx.Err() != nil {
+		outcome = "stopped"
+	}
+	log.Printf("Search %s after %d combinations in %v: the best score was improved %d times and is %v",
+		outcome, p.evaluated, time.Since(p.start).Round(time.Second), len(p.history), getBestScore())
+}
+
+// waitForSignal cancels the search when it is asked to stop, after which a
+// second signal stops the program at once.
+func waitForSignal(sigChan chan os.Signal, cancel context.CancelFunc) {
+	sig := <-sigChan
+	log.Printf("Signal %v received, stopping after writing the final checkpoint", sig)
+	signal.Reset(syscall.SIGINT, syscall.SIGTERM)
+	cancel()
+}
+
+// processCombinations searches the ranges of the iterators, of which a
+// resumed breakpoint file can hold more than there are workers.
+func processCombinations(ctx context.Context, iterators []*fixtures.FixtureListIterator, workers int, resultChan chan EvaluationResult) {
 	defer close(resultChan)
-	list := fixtures.BuildFixtureList()
-	it := list.Iterator(readBreakpoints()...)
+	runQueued(len(iterators), workers, func(worker int, position int) {
+		processRange(ctx, worker, position, iterators[position], resultChan)
+	})
+}
+
+// runQueued runs tasks 0 to n-1 on at most workers goroutines, each of
+// which takes the next task from a queue when it finishes one, and waits
+// for them all.
+func runQueued(n int, workers int, run func(worker int, task int)) {
+	queue := make(chan int, n)
+	for i := 0; i < n; i++ {
+		queue <- i
+	}
+	close(queue)
+	if workers > n {
+		workers = n
+	}
+	workerGroup := sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		workerGroup.Add(1)
+		go func(worker int) {
+			defer workerGroup.Done()
+			for task := range queue {
+				run(worker, task)
+			}
+		}(w)
+	}
+	workerGroup.Wait()
+}
+
+func processRange(ctx context.Context, worker int, position int, it *fixtures.FixtureListIterator, resultChan chan EvaluationResult) {
+	next := it.Next
+	if prune {
+		next = func() (fixtures.Schedule, bool) {
+			return it.NextBounded(scorer, pruningLimit(getBestScore()))
+		}
+	}
+	pruneCount := 0
 	for {
-		if checkForStop(stoppingChan) {
+		if checkForStop(ctx) {
 			break
 		}
-		sch, ok := it.Next()
+		sch, ok := next()
 		if !ok {
-			log.Print("All combinations processed!")
+			first, last := it.Range()
+			log.Printf("Worker %d: all combinations in range %d-%d processed (%v pruned)!", worker, first, last, it.Pruned())
 			break
 		}
 		result := EvaluationResult{
-			indices:  it.NextIndices(),
+			worker:    worker,
+			position:  position,
+			evaluated: 1,
+			indices:   it.NextIndices(),
 			schedule: sch,
-			score:    sch.Evaluate(),
+			score:    sch.ScoreWith(scorer),
+		}
+		if it.PruneCount() != pruneCount {
+			pruneCount = it.PruneCount()
+			result.pruned = it.Pruned()
 		}
 		resultChan <- result
 	}
 }
 
-func checkForStop(stoppingChan chan struct{}) bool {
-	select {
-	case <-stoppingChan:
-		return true
-	default:
-		return false
+func anneal(ctx context.Context, list fixtures.FixtureWeekList, resultChan chan EvaluationResult) {
+	defer close(resultChan)
+	s := randomSeed()
+	log.Printf("Annealing with %d workers from seed %d", workers, s)
+	workerGroup := sync.WaitGroup{}
+	workerGroup.Add(workers)
+	for i := 0; i < workers; i++ {
+		go annealChain(ctx, i, fixtures.NewAnnealer(list, scorer, s+int64(i)), resultChan, &workerGroup)
 	}
+	workerGroup.Wait()
 }
 
-func processResults(resultChan chan EvaluationResult, wg sync.WaitGroup) {
-	defer wg.Done()
-	committer := intervalProcessor(commitFrequency, func(indices []int) {
-		log.Printf("Committing after %d combinations", commitFrequency)
-		writeBreakpoints(indices)
-		if cmdout, err := exec.Command("git", "commit", "-m", "Latest status", bestFile, breakpointFile).Output(); err != nil {
-			log.Printf("Commit failed", cmdout, err)
+func annealChain(ctx context.Context, worker int, a *fixtures.Annealer, resultChan chan EvaluationResult, workerGroup *sync.WaitGroup) {
+	defer workerGroup.Done()
+	result := EvaluationResult{worker: worker, evaluated: 1}
+	result.schedule, result.score = bestOf(a)
+	resultChan <- result
+	for !checkForStop(ctx) {
+		result := EvaluationResult{worker: worker, evaluated: 1}
+		if a.Step() {
+			result.schedule, result.score = bestOf(a)
 		}
+		resultChan <- result
+	}
+}
+
+func evolve(ctx context.Context, list fixtures.FixtureWeekList, resultChan chan EvaluationResult) {
+	defer close(resultChan)
+	s := randomSeed()
+	g := fixtures.NewGeneticSearch(list, scorer, s, populationSize, readPopulation()...)
+	g.Workers = workers
+	log.Printf("Evolving a population of %d with %d workers from seed %d", populationSize, workers, s)
+	result := EvaluationResult{evaluated: populationSize, population: g.Population()}
+	result.schedule, result.score = bestOf(g)
+	resultChan <- result
+	for !checkForStop(ctx) {
+		result := EvaluationResult{evaluated: populationSize}
+		if g.Evolve() {
+			result.schedule, result.score = bestOf(g)
+			log.Printf("Generation %d improved on its predecessor", g.Generation())
+		}
+		result.population = g.Population()
+		resultChan <- result
+	}
+}
+
+func bestOf(search interface {
+	Best() (fixtures.Schedule, int)
+}) (fixtures.Schedule, fixtures.Score) {
+	schedule, _ := search.Best()
+	return schedule, schedule.ScoreWith(scorer)
+}
+
+func randomSeed() int64 {
+	if seed != 0 {
+		return seed
+	}
+	return time.Now().UnixNano()
+}
+
+// checkForStop reports whether the search has been stopped, first waiting
+// while it is paused.
+func checkForStop(ctx context.Context) bool {
+	searchGate.wait(ctx)
+	return ctx.Err() != nil
+}
+
+// processResults records the results of a search until all its workers
+// have finished, reporting its progress at intervals and whenever a signal
+// arrives on reportChan, and answering requests on controlChan. It then
+// writes a final checkpoint.
+func processResults(resultChan chan EvaluationResult, positions []breakpoint, p *progress, reportChan <-chan os.Signal, controlChan <-chan controlRequest) {
+	var population []fixtures.Genome
+	generations := 0
+	checkpoint := func() {
+		if len(positions) > 0 {
+			if err := writeBreakpoints(positions); err != nil {
+				log.Printf("File %s could not be written: %v", breakpointFile, err)
+			}
+		}
+		if population != nil {
+			if err := writePopulation(population); err != nil {
+				log.Printf("File %s could not be written: %v", populationFile, err)
+			}
+		}
+		if err := checkpointSink.Keep(checkpointFiles()); err != nil {
+			log.Printf("Checkpoint could not be kept: %v", err)
+		}
+	}
+	committer := intervalProcessor(checkpointInterval, func(result EvaluationResult) {
+		log.Printf("Checkpointing after %d combinations", checkpointInterval)
+		checkpoint()
 	})
-	logger := intervalProcessor(messageFrequency, func(indices []int) {
-		log.Printf("Processed another batch of %d combinations: latest one was %v", messageFrequency, indices)
+	pruned := make([]*big.Int, len(positions))
+	totalPruned := func() *big.Int {
+		total := big.NewInt(0)
+		for _, n := range pruned {
+			if n != nil {
+				total.Add(total, n)
+			}
+		}
+		return total
+	}
+	logger := intervalProcessor(logInterval, func(result EvaluationResult) {
+		log.Print(p.line(positions, totalPruned()))
 	})
-	for result := range resultChan {
-		if bestScore == -1 || bestScore > result.score {
+	for {
+		var result EvaluationResult
+		var ok bool
+		select {
+		case <-reportChan:
+			log.Print(p.report(positions, totalPruned()))
+			continue
+		case request := <-controlChan:
+			if request.checkpoint {
+				log.Printf("Checkpointing on request")
+				checkpoint()
+			}
+			request.reply <- p.status(positions, totalPruned())
+			continue
+		case result, ok = <-resultChan:
+		}
+		if !ok {
+			break
+		}
+		if best := getBestScore(); result.schedule != nil && result.score.Better(best) {
 			writeBest(result.schedule, result.score)
-			log.Printf("Found a better score: %d (was %d)", result.score, bestScore)
-			bestScore = result.score
+			log.Printf("Found a better score: %v (was %v)", result.score, best)
+			setBestScore(result.score)
+			p.improved(result.score)
+		}
+		p.evaluated += int64(result.evaluated)
+		if result.indices != nil {
+			positions[result.position].indices = result.indices
 		}
-		committer(result.indices)
-		logger(result.indices)
+		if result.pruned != nil {
+			pruned[result.position] = result.pruned
+		}
+		if result.population != nil {
+			population = result.population
+			if generations++; generations%populationInterval == 0 {
+				if err := writePopulation(population); err != nil {
+					log.Printf("File %s could not be written: %v", populationFile, err)
+				}
+			}
+		}
+		committer(result)
+		logger(result)
 	}
+	log.Printf("Writing the final checkpoint")
+	checkpoint()
 }
 
-func intervalProcessor(interval int, f func([]int)) func([]int) {
+// checkpointFiles returns those of the files written by the search that
+// exist.
+func checkpointFiles() []string {
+	answer := make([]string, 0)
+	for _, f := range []string{bestFile, reportFile, breakpointFile, populationFile} {
+		if _, err := os.Stat(f); err == nil {
+			answer = append(answer, f)
+		}
+	}
+	return answer
+}
+
+func intervalProcessor(interval int, f func(EvaluationResult)) func(EvaluationResult) {
 	count := 0
-	return func(indices []int) {
-		count++
+	return func(result EvaluationResult) {
+		count += result.evaluated
 		if count >= interval {
 			count = 0
-			f(indices)
+			f(result)
+		}
+	}
+}
+
+func readFixtureList(name string) fixtures.FixtureWeekList {
+	list, err := fixtures.ReadFixtureList(name)
+	if err != nil {
+		log.Fatalf("Fixture list could not be loaded: %v", err)
+	}
+	log.Printf("Loaded %d weeks from file %s", len(list), name)
+	problems := fixtures.Validate(list)
+	for _, p := range problems {
+		log.Print(p)
+	}
+	if problems.HasErrors() {
+		log.Fatalf("Fixture list in file %s is not valid: %d errors found", name, len(problems.Errors()))
+	}
+	return list
+}
+
+func readScoring() {
+	if scoringFile == "" {
+		return
+	}
+	config, err := fixtures.ReadScoringConfig(scoringFile)
+	if err != nil {
+		log.Fatalf("Scoring could not be loaded: %v", err)
+	}
+	if _, err = fixtures.NewScorer(config); err != nil {
+		log.Fatalf("Scoring in file %s is not valid: %v", scoringFile, err)
+	}
+	scoringConfig = config
+	log.Printf("Loaded %d scoring criteria from file %s", len(config.Criteria), scoringFile)
+}
+
+// fitScoring makes the court criteria count the season's courts, warning
+// about any court values given in the scoring file that this replaces,
+// reads the scoring's times on the season's clock and builds the scorer
+// from the result. Every command that scores a schedule calls it once it
+// has read the season.
+func fitScoring(list fixtures.FixtureWeekList) {
+	courts := list.Courts()
+	for i, c := range scoringConfig.Criteria {
+		if c.Attribute == "court" && len(c.Values) > 0 && strings.Join(c.Values, ",") != strings.Join(courts, ",") {
+			log.Printf("Scoring criterion %d: court values %v replaced by the season's courts %v", i+1, c.Values, courts)
 		}
 	}
+	config := scoringConfig.WithCourts(courts).OnClock(list.Season().Clock)
+	s, err := fixtures.NewScorer(config)
+	if err != nil {
+		log.Fatalf("Scoring is not valid for the season: %v", err)
+	}
+	scoringConfig, scorer = config, s
 }
 
-func readBreakpoints() []int {
-	data, read := readFile(breakpointFile)
+func readBreakpoints(list fixtures.FixtureWeekList) []*fixtures.FixtureListIterator {
+	data, read := readCheckpoint(breakpointFile)
 	if !read {
 		log.Printf("File %s not found", breakpointFile)
-		return []int{}
+		return []*fixtures.FixtureListIterator{list.Iterator()}
+	}
+	if breakpoints := parseBreakpoints(data); breakpoints != nil {
+		log.Printf("Found breakpoints in file %s: %v", breakpointFile, breakpoints)
+		return []*fixtures.FixtureListIterator{list.Iterator(breakpoints...)}
 	}
-	breakpoints := parseBreakpoints(data)
+	breakpoints := parseRangeBreakpoints(data)
 	if breakpoints == nil {
 		log.Fatalf("File %s found but is not valid in format", breakpointFile)
 	}
-	log.Printf("Found breakpoints in file %s: %v", breakpointFile, breakpoints)
-	return breakpoints
+	answer := make([]*fixtures.FixtureListIterator, len(breakpoints))
+	for i, bp := range breakpoints {
+		log.Printf("Found breakpoints in file %s for range %d-%d: %v", breakpointFile, bp.first, bp.last, bp.indices)
+		answer[i] = list.RangeIterator(bp.first, bp.last, bp.indices...)
+	}
+	return answer
+}
+
+func splitIterators(iterators []*fixtures.FixtureListIterator, parts int) []*fixtures.FixtureListIterator {
+	for len(iterators) < parts {
+		largest := 0
+		for i, it := range iterators {
+			if it.Remaining() > iterators[largest].Remaining() {
+				largest = i
+			}
+		}
+		if iterators[largest].Remaining() < 2 {
+			break
+		}
+		split := iterators[largest].Split(2)
+		iterators = append(append(iterators[:largest:largest], split...), iterators[largest+1:]...)
+	}
+	return iterators
 }
 
 func parseBreakpoints(data []byte) []int {
@@ -137,12 +497,70 @@ func parseBreakpoints(data []byte) []int {
 	return islice
 }
 
-func writeBreakpoints(data []int) {
+func parseRangeBreakpoints(data []byte) []breakpoint {
+	r, _ := regexp.Compile("^\\s*(\\d+)\\s+(\\d+)\\s*:((\\s*\\d+)+)\\s*$")
+	answer := make([]breakpoint, 0)
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		groups := r.FindStringSubmatch(line)
+		if groups == nil {
+			return nil
+		}
+		bp := breakpoint{indices: parseBreakpoints([]byte(groups[3]))}
+		bp.first, _ = strconv.Atoi(groups[1])
+		bp.last, _ = strconv.Atoi(groups[2])
+		answer = append(answer, bp)
+	}
+	if len(answer) == 0 {
+		return nil
+	}
+	return answer
+}
+
+func writeBreakpoints(positions []breakpoint) error {
 	var buffer bytes.Buffer
-	for _, v := range data {
-		buffer.WriteString(fmt.Sprintf("%d ", v))
+	for _, bp := range positions {
+		buffer.WriteString(fmt.Sprintf("%d %d:", bp.first, bp.last))
+		for _, v := range bp.indices {
+			buffer.WriteString(fmt.Sprintf(" %d", v))
+		}
+		buffer.WriteString("\n")
 	}
-	ioutil.WriteFile(breakpointFile, buffer.Bytes(), 0644)
+	return writeCheckpoint(breakpointFile, buffer.Bytes())
+}
+
+func readPopulation() []fixtures.Genome {
+	data, read := readCheckpoint(populationFile)
+	if !read {
+		log.Printf("File %s not found", populationFile)
+		return nil
+	}
+	answer := make([]fixtures.Genome, 0)
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		genome := parseBreakpoints([]byte(line))
+		if genome == nil {
+			log.Fatalf("File %s found but is not valid in format", populationFile)
+		}
+		answer = append(answer, genome)
+	}
+	log.Printf("Found %d schedules in file %s", len(answer), populationFile)
+	return answer
+}
+
+func writePopulation(population []fixtures.Genome) error {
+	var buffer bytes.Buffer
+	for _, genome := range population {
+		for _, v := range genome {
+			buffer.WriteString(fmt.Sprintf("%d ", v))
+		}
+		buffer.WriteString("\n")
+	}
+	return writeCheckpoint(populationFile, buffer.Bytes())
 }
 
 func readFile(name string) ([]byte, bool) {
@@ -161,38 +579,189 @@ func readFile(name string) ([]byte, bool) {
 	return answer, true
 }
 
-func readBestScore() int {
+func getBestScore() fixtures.Score {
+	score, _ := bestScore.Load().(fixtures.Score)
+	return score
+}
+
+func setBestScore(score fixtures.Score) {
+	bestScore.Store(score)
+}
+
+// pruningLimit returns the worst team score above which a partial schedule
+// can be skipped; one that only equals the best's worst team can still win
+// on the later levels of the score.
+func pruningLimit(best fixtures.Score) int {
+	if best == nil {
+		return -1
+	}
+	return best.Worst() + 1
+}
+
+// readBestScore returns the score of the best file, which prunes the
+// search. A score recorded under other scoring, or in a file without a
+// header to say, is not used, since it could prune schedules that are
+// better under the scoring being searched.
+func readBestScore() fixtures.Score {
 	data, read := readFile(bestFile)
 	if !read {
 		log.Printf("File %s not found", bestFile)
-		return -1
+		return nil
 	}
-	best, err := parseBestScore(data)
+	best, err := parseBestScore(data, currentCheckpoint())
 	if err != nil {
-		log.Fatalf("File %s found but is not valid in format: %v", bestFile, err)
-		os.Exit(1)
+		log.Fatalf("File %s cannot be resumed: %v", bestFile, err)
 	}
-	log.Printf("Found best score in file %s: %d", bestFile, best)
+	if best == nil {
+		log.Printf("File %s was not written with these scoring criteria, so its score is not used", bestFile)
+		return nil
+	}
+	log.Printf("Found best score in file %s: %v", bestFile, best)
 	return best
 }
 
-func parseBestScore(data []byte) (int, error) {
-	r, _ := regexp.Compile("\\d+")
-	best, err := strconv.Atoi(string(r.Find(data)))
+// parseBestScore returns the score recorded in the header of a best file,
+// or nil if it has no header or was written with other scoring. Like
+// parseCheckpoint it refuses a file written for a different fixture list.
+func parseBestScore(data []byte, expected checkpoint) (fixtures.Score, error) {
+	header, _, err := parseCheckpoint(data, expected)
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+	if header == nil || !sameScoring(header.Scoring, expected.Scoring) {
+		return nil, nil
 	}
-	return best, nil
+	return fixtures.ParseScore(header.Best)
 }
 
-func writeBest(schedule fixtures.Schedule, score int) {
-	var buffer bytes.Buffer
-	buffer.WriteString(fmt.Sprintf("%d\n%v", score, schedule.String()))
-	ioutil.WriteFile(bestFile, buffer.Bytes(), 644)
+// writeBest writes the best file, headed by the season and scoring it was
+// found for, and its report. The header leaves out the enumeration, which
+// does not change the score of a schedule.
+func writeBest(schedule fixtures.Schedule, score fixtures.Score) {
+	header := currentCheckpoint()
+	header.Enumeration = ""
+	header.Best = score.String()
+	if data, err := formatCheckpoint(header, []byte(fmt.Sprintf("%v\n%v", score, schedule.String()))); err != nil {
+		log.Printf("File %s could not be written: %v", bestFile, err)
+	} else if err := writeFileAtomic(bestFile, data); err != nil {
+		log.Printf("File %s could not be written: %v", bestFile, err)
+	}
+	if err := writeFileAtomic(reportFile, []byte(schedule.Report(scoringConfig).String())); err != nil {
+		log.Printf("File %s could not be written: %v", reportFile, err)
+	}
+}
+
+func generateSeason(name string) {
+	league, err := fixtures.ReadLeague(name)
+	if err != nil {
+		log.Fatalf("League could not be loaded: %v", err)
+	}
+	list, err := league.Generate()
+	if err != nil {
+		log.Fatalf("Season could not be generated from file %s: %v", name, err)
+	}
+	for _, p := range fixtures.Validate(list) {
+		log.Print(p)
+	}
+	if err := fixtures.WriteFixtureList(os.Stdout, list); err != nil {
+		log.Fatalf("Season could not be written: %v", err)
+	}
+	log.Printf("Generated %d weeks from file %s", len(list), name)
+}
+
+// readLocatedSchedule reads the season and a schedule file, the best file
+// if none is named, and places the schedule's matches in the season's weeks.
+func readLocatedSchedule(seasonName string, scheduleName string) (fixtures.FixtureWeekList, fixtures.Schedule) {
+	if scheduleName == "" {
+		scheduleName = bestFile
+	}
+	list := readFixtureList(seasonName)
+	return list, locateSchedule(list, scheduleName)
+}
+
+// locateSchedule reads a schedule file, checks that it arranges the season
+// and places its matches in the season's weeks.
+func locateSchedule(list fixtures.FixtureWeekList, scheduleName string) fixtures.Schedule {
+	schedule, err := fixtures.ReadSchedule(scheduleName)
+	if err != nil {
+		log.Fatalf("Schedule could not be loaded: %v", err)
+	}
+	problems := fixtures.ValidateSchedule(list, schedule)
+	for _, p := range problems {
+		log.Print(p)
+	}
+	if problems.HasErrors() {
+		log.Fatalf("Schedule in file %s does not fit the season: %d errors found", scheduleName, len(problems.Errors()))
+	}
+	if schedule, err = list.Locate(schedule); err != nil {
+		log.Fatalf("Schedule in file %s does not fit the season: %v", scheduleName, err)
+	}
+	return schedule
+}
+
+func evaluateSchedule(seasonName string, scheduleName string) {
+	list, schedule := readLocatedSchedule(seasonName, scheduleName)
+	fitScoring(list)
+	fmt.Printf("Evaluation %d\nScore %v\n%v", schedule.EvaluateWith(scorer), schedule.ScoreWith(scorer), schedule.Report(scoringConfig))
+}
+
+func writeCalendars(seasonName string, scheduleName string) {
+	_, schedule := readLocatedSchedule(seasonName, scheduleName)
+	if err := fixtures.WriteCalendars(calendarDir, schedule); err != nil {
+		log.Fatalf("Calendars could not be written: %v", err)
+	}
+	log.Printf("Wrote calendars of %d matches to directory %s", len(schedule), calendarDir)
+}
+
+func exportSchedule(seasonName string, scheduleName string) {
+	f, err := fixtures.ParseScheduleFormat(format)
+	if err != nil {
+		log.Fatal(err)
+	}
+	_, schedule := readLocatedSchedule(seasonName, scheduleName)
+	if err := fixtures.WriteSchedule(os.Stdout, schedule, f); err != nil {
+		log.Fatalf("Schedule could not be written: %v", err)
+	}
+	log.Printf("Exported %d matches as %v", len(schedule), f)
+}
+
+func reportSchedules(seasonName string, names []string) {
+	if len(names) == 0 {
+		names = []string{bestFile}
+	}
+	list := readFixtureList(seasonName)
+	fitScoring(list)
+	for _, name := range names {
+		schedule := locateSchedule(list, name)
+		if len(names) > 1 {
+			fmt.Printf("%s:\n", name)
+		}
+		fmt.Printf("Score %v\n%v", schedule.ScoreWith(scorer), schedule.Report(scoringConfig))
+	}
+}
+
+type breakpoint struct {
+	first   int
+	last    int
+	indices []int
+}
+
+func newBreakpoint(it *fixtures.FixtureListIterator) breakpoint {
+	first, last := it.Range()
+	return breakpoint{
+		first:   first,
+		last:    last,
+		indices: it.NextIndices(),
+	}
 }
 
 type EvaluationResult struct {
-	indices  []int
-	schedule fixtures.Schedule
-	score    int
+	worker     int
+	position   int
+	evaluated  int
+	indices    []int
+	schedule   fixtures.Schedule
+	score      fixtures.Score
+	pruned     *big.Int
+	population []fixtures.Genome
 }
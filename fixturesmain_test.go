@@ -1,10 +1,28 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"github.com/stretchr/testify/assert"
+	"fixtures/fixtures"
 )
 
+func buildFixtureList(t *testing.T) fixtures.FixtureWeekList {
+	answer, err := fixtures.BuildFixtureList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return answer
+}
+
 func TestParseBreakpointsNotValid(t *testing.T) {
 	checker := func(s string) {
 		bp := parseBreakpoints([]byte(s))
@@ -19,3 +37,257 @@ func TestParseBreakpointsValid(t *testing.T) {
 	bp := parseBreakpoints([]byte("   1241   32  1 3   1  "))
 	assert.Equal(t, []int{1241, 32, 1, 3, 1}, bp)
 }
+
+func TestParseRangeBreakpointsValid(t *testing.T) {
+	bp := parseRangeBreakpoints([]byte("0 360: 12 0 5\n 360 720 :360 1 2 \n\n"))
+	assert.Equal(t, []breakpoint{
+		{first: 0, last: 360, indices: []int{12, 0, 5}},
+		{first: 360, last: 720, indices: []int{360, 1, 2}},
+	}, bp)
+}
+
+func TestParseRangeBreakpointsNotValid(t *testing.T) {
+	checker := func(s string) {
+		bp := parseRangeBreakpoints([]byte(s))
+		assert.Nil(t, bp)
+	}
+	checker("")
+	checker("1 2 3")
+	checker("0 360: 1 2\nrubbish")
+	checker("0: 1 2")
+	checker("0 360:")
+}
+
+func TestSplitIterators(t *testing.T) {
+	list := fixtures.FixtureWeekList{
+		fixtures.NewWeek("31 May", 1, 2, true,
+			fixtures.NewMatch("11", "12"),
+			fixtures.NewMatch("13", "14"),
+			fixtures.NewMatch("15", "16")),
+		fixtures.NewWeek("1 Jun", 3, 4, true,
+			fixtures.NewMatch("21", "22"),
+			fixtures.NewMatch("23", "24"),
+			fixtures.NewMatch("25", "26")),
+	}
+	iterators := splitIterators([]*fixtures.FixtureListIterator{list.Iterator(1, 3)}, 4)
+	assert.Equal(t, 4, len(iterators))
+	ranges := make([]breakpoint, len(iterators))
+	for i, it := range iterators {
+		ranges[i] = newBreakpoint(it)
+	}
+	assert.Equal(t, []breakpoint{
+		{first: 0, last: 2, indices: []int{1, 3}},
+		{first: 2, last: 3, indices: []int{2, 0}},
+		{first: 3, last: 4, indices: []int{3, 0}},
+		{first: 4, last: 6, indices: []int{4, 0}},
+	}, ranges)
+	assert.Equal(t, 2, len(splitIterators([]*fixtures.FixtureListIterator{list.Iterator(4)}, 3)))
+}
+
+func TestRunQueued(t *testing.T) {
+	var running, most int32
+	var mutex sync.Mutex
+	ran := make(map[int]int)
+	runQueued(5, 2, func(worker int, task int) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		mutex.Lock()
+		defer mutex.Unlock()
+		if n > most {
+			most = n
+		}
+		assert.True(t, worker < 2)
+		ran[task]++
+	})
+	assert.Equal(t, map[int]int{0: 1, 1: 1, 2: 1, 3: 1, 4: 1}, ran)
+	assert.True(t, most <= 2)
+}
+
+func TestProcessCombinationsResumesMoreRangesThanWorkers(t *testing.T) {
+	list := fixtures.FixtureWeekList{
+		fixtures.NewWeek("31 May", 1, 2, true,
+			fixtures.NewMatch("11", "12"),
+			fixtures.NewMatch("13", "14"),
+			fixtures.NewMatch("15", "16")),
+		fixtures.NewWeek("1 Jun", 3, 4, true,
+			fixtures.NewMatch("21", "22"),
+			fixtures.NewMatch("23", "24"),
+			fixtures.NewMatch("25", "26")),
+	}
+	saved := scorer
+	defer func() { scorer = saved }()
+	scorer = fixtures.DefaultScorer
+	iterators := []*fixtures.FixtureListIterator{
+		list.RangeIterator(0, 2),
+		list.RangeIterator(2, 3),
+		list.RangeIterator(3, 4),
+		list.RangeIterator(4, 6),
+	}
+	resultChan := make(chan EvaluationResult, 10)
+	go processCombinations(context.Background(), iterators, 2, resultChan)
+	positions := make(map[int]int)
+	for result := range resultChan {
+		assert.True(t, result.worker < 2)
+		positions[result.position]++
+	}
+	assert.Equal(t, map[int]int{0: 12, 1: 6, 2: 6, 3: 12}, positions)
+}
+
+func TestParseBestScore(t *testing.T) {
+	expected := checkpoint{Version: checkpointVersion, Season: "abc", Enumeration: "pairings", Scoring: fixtures.DefaultScoringConfig()}
+	header := checkpoint{Version: checkpointVersion, Season: "abc", Scoring: fixtures.DefaultScoringConfig(), Best: "164 150 150 20"}
+	data, _ := formatCheckpoint(header, []byte("164 150 150 20\n30 Sep, 6.15, A: 25 v 26\n"))
+	score, err := parseBestScore(data, expected)
+	assert.Nil(t, err)
+	assert.Equal(t, fixtures.Score{164, 150, 150, 20}, score)
+
+	other := expected
+	other.Scoring = fixtures.ScoringConfig{Criteria: []fixtures.Criterion{{Attribute: "court", Weight: 1}}}
+	score, err = parseBestScore(data, other)
+	assert.Nil(t, err)
+	assert.Nil(t, score)
+
+	score, err = parseBestScore([]byte("164\n30 Sep, 6.15, A: 25 v 26\n"), expected)
+	assert.Nil(t, err)
+	assert.Nil(t, score)
+
+	other = expected
+	other.Season = "def"
+	_, err = parseBestScore(data, other)
+	assert.EqualError(t, err, "it belongs to a different fixture list")
+
+	header.Best = ""
+	data, _ = formatCheckpoint(header, []byte("30 Sep, 6.15, A: 25 v 26\n"))
+	_, err = parseBestScore(data, expected)
+	assert.NotNil(t, err)
+}
+
+func TestWriteBest(t *testing.T) {
+	dir := t.TempDir()
+	savedFiles := []string{bestFile, reportFile}
+	savedHash, savedScoring := seasonHash, scoringConfig
+	defer func() {
+		bestFile, reportFile = savedFiles[0], savedFiles[1]
+		seasonHash, scoringConfig = savedHash, savedScoring
+	}()
+	bestFile, reportFile = filepath.Join(dir, "best"), filepath.Join(dir, "report")
+	seasonHash, scoringConfig = "abc", fixtures.DefaultScoringConfig()
+	schedule := fixtures.Schedule{fixtures.NewScheduledMatch(fixtures.NewMatch("25", "26"), "30 Sep", fixtures.At(18, 15), "A")}
+	writeBest(schedule, fixtures.Score{164, 150})
+	assert.Equal(t, fixtures.Score{164, 150}, readBestScore())
+	loaded, err := fixtures.ReadSchedule(bestFile)
+	assert.Nil(t, err)
+	assert.Equal(t, schedule.String(), loaded.String())
+
+	scoringConfig = fixtures.ScoringConfig{Criteria: []fixtures.Criterion{{Attribute: "court", Weight: 1}}}
+	assert.Nil(t, readBestScore())
+}
+
+func TestPruningLimit(t *testing.T) {
+	assert.Equal(t, -1, pruningLimit(nil))
+	assert.Equal(t, 165, pruningLimit(fixtures.Score{164, 150}))
+}
+
+func TestFindCommand(t *testing.T) {
+	assert.Equal(t, "evaluate", findCommand("evaluate").name)
+	assert.Nil(t, findCommand("anneal"))
+	c := findCommand("search")
+	fs := c.flagSet()
+	assert.NotNil(t, fs.Lookup("mode"))
+	assert.NotNil(t, fs.Lookup("solver"))
+	assert.Nil(t, fs.Lookup("format"))
+}
+
+func TestSetOutputDir(t *testing.T) {
+	saved := []string{bestFile, breakpointFile, populationFile, reportFile}
+	defer func() {
+		bestFile, breakpointFile, populationFile, reportFile = saved[0], saved[1], saved[2], saved[3]
+	}()
+	dir := filepath.Join(t.TempDir(), "2019")
+	assert.Nil(t, setOutputDir(dir))
+	assert.DirExists(t, dir)
+	assert.Equal(t, filepath.Join(dir, "best"), bestFile)
+	assert.Equal(t, filepath.Join(dir, "breakpoint"), breakpointFile)
+}
+
+func TestProcessResultsWritesFinalCheckpoint(t *testing.T) {
+	saved := []string{bestFile, breakpointFile, populationFile, reportFile}
+	defer func() {
+		bestFile, breakpointFile, populationFile, reportFile = saved[0], saved[1], saved[2], saved[3]
+		setBestScore(nil)
+	}()
+	assert.Nil(t, setOutputDir(t.TempDir()))
+	resultChan := make(chan EvaluationResult, 2)
+	resultChan <- EvaluationResult{position: 0, evaluated: 1, indices: []int{1, 2}}
+	resultChan <- EvaluationResult{position: 1, evaluated: 1, indices: []int{3, 4}}
+	close(resultChan)
+	positions := []breakpoint{{first: 0, last: 2}, {first: 2, last: 4}}
+	p := newProgress(buildFixtureList(t), positions)
+	processResults(resultChan, positions, p, nil, nil)
+	assert.Equal(t, int64(2), p.evaluated)
+	data, _ := ioutil.ReadFile(breakpointFile)
+	_, body, err := parseCheckpoint(data, currentCheckpoint())
+	assert.Nil(t, err)
+	assert.Equal(t, "0 2: 1 2\n2 4: 3 4\n", string(body))
+}
+
+func TestProcessResultsWritesPopulationEveryInterval(t *testing.T) {
+	saved := []string{bestFile, breakpointFile, populationFile, reportFile}
+	defer func() {
+		bestFile, breakpointFile, populationFile, reportFile = saved[0], saved[1], saved[2], saved[3]
+	}()
+	assert.Nil(t, setOutputDir(t.TempDir()))
+	resultChan := make(chan EvaluationResult)
+	go func() {
+		for i := 0; i < populationInterval; i++ {
+			resultChan <- EvaluationResult{evaluated: 1, population: []fixtures.Genome{{i, 1}}}
+		}
+		// Once this is received, the previous result has been processed.
+		resultChan <- EvaluationResult{evaluated: 1, population: []fixtures.Genome{{99, 1}}}
+		data, _ := ioutil.ReadFile(populationFile)
+		_, body, _ := parseCheckpoint(data, currentCheckpoint())
+		assert.Equal(t, fmt.Sprintf("%d 1 \n", populationInterval-1), string(body))
+		close(resultChan)
+	}()
+	processResults(resultChan, nil, newProgress(buildFixtureList(t), nil), nil, nil)
+	data, _ := ioutil.ReadFile(populationFile)
+	_, body, _ := parseCheckpoint(data, currentCheckpoint())
+	assert.Equal(t, "99 1 \n", string(body))
+}
+
+func TestWriteBestWritesReadableFiles(t *testing.T) {
+	saved := []string{bestFile, breakpointFile, populationFile, reportFile}
+	defer func() {
+		bestFile, breakpointFile, populationFile, reportFile = saved[0], saved[1], saved[2], saved[3]
+	}()
+	assert.Nil(t, setOutputDir(t.TempDir()))
+	list := buildFixtureList(t)
+	s := list.Combination(make([]int, len(list))...)
+	writeBest(s, s.Score())
+	for _, name := range []string{bestFile, reportFile} {
+		info, err := os.Stat(name)
+		assert.Nil(t, err)
+		assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+	}
+	assert.Equal(t, []string{bestFile, reportFile}, checkpointFiles())
+}
+
+func TestFitScoring(t *testing.T) {
+	savedConfig, savedScorer := scoringConfig, scorer
+	defer func() {
+		scoringConfig, scorer = savedConfig, savedScorer
+		log.SetOutput(os.Stderr)
+	}()
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	list := buildFixtureList(t)
+	scoringConfig = fixtures.DefaultScoringConfig()
+	fitScoring(list)
+	assert.Empty(t, logged.String())
+	scoringConfig.Criteria[1].Values = []string{"A", "B", "C"}
+	fitScoring(list)
+	assert.Contains(t, logged.String(), "Scoring criterion 2: court values [A B C] replaced by the season's courts [A B]")
+	assert.Equal(t, list.Courts(), scoringConfig.Criteria[1].Values)
+	s := list.Combination(make([]int, len(list))...)
+	assert.Equal(t, s.ScoreWith(fixtures.DefaultScorer), s.ScoreWith(scorer))
+}
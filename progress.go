@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"fixtures/fixtures"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+)
+
+// heatDeathYears is roughly how long the universe has left, beyond which
+// an estimate of when a search will finish is of no practical interest.
+const heatDeathYears = 1e100
+
+const secondsPerYear = 365.25 * 24 * 60 * 60
+
+// progress tracks how far a search has gone and how its best score has
+// improved. Time spent paused does not count.
+type progress struct {
+	list  fixtures.FixtureWeekList
+	start time.Time
+	// pausedBefore is how long the search gate had been paused before the
+	// search started.
+	pausedBefore time.Duration
+	evaluated    int64
+	history      []improvement
+	// initial is how much of the space was left when the search started, or
+	// nil for a search, such as annealing, that does not visit the whole
+	// space.
+	initial *big.Int
+}
+
+type improvement struct {
+	elapsed time.Duration
+	score   fixtures.Score
+}
+
+func newProgress(list fixtures.FixtureWeekList, positions []breakpoint) *progress {
+	answer := &progress{list: list, start: time.Now(), pausedBefore: searchGate.pausedFor()}
+	if len(positions) > 0 {
+		answer.initial = answer.remaining(positions)
+	}
+	return answer
+}
+
+// elapsed returns how long the search has run, leaving out the time it
+// has spent paused.
+func (p *progress) elapsed() time.Duration {
+	return time.Since(p.start) - (searchGate.pausedFor() - p.pausedBefore)
+}
+
+func (p *progress) improved(score fixtures.Score) {
+	p.history = append(p.history, improvement{elapsed: p.elapsed(), score: score})
+}
+
+// remaining returns the number of schedules the workers have still to visit
+// or prune, from the position each has reached in its range.
+func (p *progress) remaining(positions []breakpoint) *big.Int {
+	answer := big.NewInt(0)
+	for _, bp := range positions {
+		left := new(big.Int).Sub(p.list.Rank(bp.last), p.list.Rank(bp.indices...))
+		if left.Sign() > 0 {
+			answer.Add(answer, left)
+		}
+	}
+	return answer
+}
+
+// line summarises the progress in one line.
+func (p *progress) line(positions []breakpoint, pruned *big.Int) string {
+	elapsed := p.elapsed()
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "Processed %d combinations in %v (%.0f/s)", p.evaluated, elapsed.Round(time.Second), float64(p.evaluated)/elapsed.Seconds())
+	if p.initial != nil {
+		remaining := p.remaining(positions)
+		size := p.list.Size()
+		done := new(big.Int).Sub(size, remaining)
+		fmt.Fprintf(&b, ", %s of the space searched (%v pruned), finishing %s", percentage(done, size), pruned, eta(new(big.Int).Sub(p.initial, remaining), remaining, elapsed))
+	}
+	fmt.Fprintf(&b, "; best score improved %d times", len(p.history))
+	if len(p.history) > 0 {
+		fmt.Fprintf(&b, ", last after %v", p.history[len(p.history)-1].elapsed.Round(time.Second))
+	}
+	return b.String()
+}
+
+// report describes the progress in full, with the history of the best
+// score.
+func (p *progress) report(positions []breakpoint, pruned *big.Int) string {
+	var b bytes.Buffer
+	b.WriteString(p.line(positions, pruned))
+	b.WriteString("\nBest score history:")
+	if len(p.history) == 0 {
+		b.WriteString(" none found")
+	}
+	for _, i := range p.history {
+		fmt.Fprintf(&b, "\n  after %v: %v", i.elapsed.Round(time.Second), i.score)
+	}
+	return b.String()
+}
+
+func percentage(part *big.Int, whole *big.Int) string {
+	if whole.Sign() == 0 {
+		return "100%"
+	}
+	f, _ := new(big.Rat).SetFrac(part, whole).Float64()
+	return fmt.Sprintf("%.6g%%", f*100)
+}
+
+// eta estimates when the search will finish from the rate at which it has
+// covered the space so far.
+func eta(covered *big.Int, remaining *big.Int, elapsed time.Duration) string {
+	if remaining.Sign() == 0 {
+		return "now"
+	}
+	if covered.Sign() <= 0 || elapsed <= 0 {
+		return "at an unknown time"
+	}
+	ratio, _ := new(big.Rat).SetFrac(remaining, covered).Float64()
+	seconds := ratio * elapsed.Seconds()
+	years := seconds / secondsPerYear
+	switch {
+	case math.IsInf(years, 0):
+		return "after the heat death of the universe"
+	case years > heatDeathYears:
+		return fmt.Sprintf("after the heat death of the universe (in %.3g years)", years)
+	case years >= 1:
+		return fmt.Sprintf("in %.3g years", years)
+	}
+	return fmt.Sprintf("in %v", time.Duration(seconds*float64(time.Second)).Round(time.Second))
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fixtures/fixtures"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestProgressRemaining(t *testing.T) {
+	list := buildFixtureList(t)
+	rest := list.Rank(1)
+	positions := []breakpoint{{first: 0, last: 2, indices: []int{0}}, {first: 2, last: 4, indices: []int{3}}}
+	p := newProgress(list, positions)
+	assert.Equal(t, new(big.Int).Mul(rest, big.NewInt(3)), p.initial)
+	positions[1].indices = []int{4}
+	assert.Equal(t, new(big.Int).Mul(rest, big.NewInt(2)), p.remaining(positions))
+	assert.Nil(t, newProgress(list, nil).initial)
+}
+
+func TestProgressReport(t *testing.T) {
+	p := newProgress(buildFixtureList(t), nil)
+	p.evaluated = 42
+	assert.Regexp(t, "^Processed 42 combinations in 0s \\(\\d+/s\\); best score improved 0 times\nBest score history: none found$", p.report(nil, nil))
+	p.improved(fixtures.Score{164, 150})
+	assert.Regexp(t, "best score improved 1 times, last after 0s\nBest score history:\n  after 0s: 164 150$", p.report(nil, nil))
+}
+
+func TestProgressLeavesOutPausedTime(t *testing.T) {
+	p := newProgress(buildFixtureList(t), nil)
+	searchGate.pause()
+	time.Sleep(20 * time.Millisecond)
+	searchGate.resume()
+	assert.True(t, p.elapsed() <= time.Since(p.start)-20*time.Millisecond)
+}
+
+func TestPercentage(t *testing.T) {
+	assert.Equal(t, "25%", percentage(big.NewInt(1), big.NewInt(4)))
+	assert.Equal(t, "100%", percentage(big.NewInt(0), big.NewInt(0)))
+}
+
+func TestETA(t *testing.T) {
+	assert.Equal(t, "now", eta(big.NewInt(5), big.NewInt(0), time.Minute))
+	assert.Equal(t, "at an unknown time", eta(big.NewInt(0), big.NewInt(5), time.Minute))
+	assert.Equal(t, "in 2m0s", eta(big.NewInt(5), big.NewInt(10), time.Minute))
+	assert.Equal(t, "in 1.9 years", eta(big.NewInt(1), big.NewInt(1000000), time.Minute))
+	huge := new(big.Int).Exp(big.NewInt(10), big.NewInt(110), nil)
+	assert.Regexp(t, "^after the heat death of the universe \\(in 1\\.9e\\+\\d+ years\\)$", eta(big.NewInt(1), huge, time.Minute))
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// reportSignals ask a search to report its progress in full.
+var reportSignals = []os.Signal{syscall.SIGUSR1}
@@ -0,0 +1,7 @@
+package main
+
+import "os"
+
+// reportSignals ask a search to report its progress in full; Windows has
+// no signal to spare for it.
+var reportSignals = []os.Signal{}
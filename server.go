@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fixtures/fixtures"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var httpAddress = ""
+
+// gate holds the search workers while the search is paused. Workers check
+// it for every schedule, so while the gate is open they only read the
+// paused flag, and take the lock only once it is set.
+type gate struct {
+	paused int32
+	mutex  sync.Mutex
+	open   chan struct{}
+	// pausedAt is when the current pause began, and pausedTotal how long
+	// the earlier pauses lasted.
+	pausedAt    time.Time
+	pausedTotal time.Duration
+}
+
+func newGate() *gate {
+	open := make(chan struct{})
+	close(open)
+	return &gate{open: open}
+}
+
+var searchGate = newGate()
+
+func (g *gate) pause() {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if !g.isPaused() {
+		g.open = make(chan struct{})
+		g.pausedAt = time.Now()
+		atomic.StoreInt32(&g.paused, 1)
+	}
+}
+
+func (g *gate) resume() {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if g.isPaused() {
+		close(g.open)
+		g.pausedTotal += time.Since(g.pausedAt)
+		atomic.StoreInt32(&g.paused, 0)
+	}
+}
+
+func (g *gate) isPaused() bool {
+	return atomic.LoadInt32(&g.paused) != 0
+}
+
+// pausedFor returns how long the search has spent paused.
+func (g *gate) pausedFor() time.Duration {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if g.isPaused() {
+		return g.pausedTotal + time.Since(g.pausedAt)
+	}
+	return g.pausedTotal
+}
+
+// wait returns once the gate is open or the search is stopped.
+func (g *gate) wait(ctx context.Context) {
+	if !g.isPaused() {
+		return
+	}
+	g.mutex.Lock()
+	open := g.open
+	g.mutex.Unlock()
+	select {
+	case <-open:
+	case <-ctx.Done():
+	}
+}
+
+// controlRequest asks processResults for the status of the search, first
+// writing a checkpoint if asked to.
+type controlRequest struct {
+	checkpoint bool
+	reply      chan searchStatus
+}
+
+type searchStatus struct {
+	Mode         string           `json:"mode"`
+	State        string           `json:"state"`
+	Evaluated    int64            `json:"evaluated"`
+	Elapsed      float64          `json:"elapsedSeconds"`
+	PerSecond    float64          `json:"perSecond"`
+	Complete     *float64         `json:"complete,omitempty"`
+	Finishing    string           `json:"finishing,omitempty"`
+	Pruned       string           `json:"pruned,omitempty"`
+	Positions    []positionStatus `json:"positions,omitempty"`
+	Improvements int              `json:"improvements"`
+	BestScore    fixtures.Score   `json:"bestScore"`
+	BestSchedule json.RawMessage  `json:"bestSchedule,omitempty"`
+}
+
+type positionStatus struct {
+	First   int   `json:"first"`
+	Last    int   `json:"last"`
+	Indices []int `json:"indices"`
+}
+
+func (p *progress) status(positions []breakpoint, pruned *big.Int) searchStatus {
+	elapsed := p.elapsed()
+	answer := searchStatus{
+		Mode:         mode,
+		State:        "running",
+		Evaluated:    p.evaluated,
+		Elapsed:      elapsed.Seconds(),
+		PerSecond:    float64(p.evaluated) / elapsed.Seconds(),
+		Improvements: len(p.history),
+		BestScore:    getBestScore(),
+	}
+	if searchGate.isPaused() {
+		answer.State = "paused"
+	}
+	if p.initial != nil {
+		remaining := p.remaining(positions)
+		size := p.list.Size()
+		complete, _ := new(big.Rat).SetFrac(new(big.Int).Sub(size, remaining), size).Float64()
+		answer.Complete = &complete
+		answer.Finishing = eta(new(big.Int).Sub(p.initial, remaining), remaining, elapsed)
+		answer.Pruned = pruned.String()
+	}
+	for _, bp := range positions {
+		answer.Positions = append(answer.Positions, positionStatus{First: bp.first, Last: bp.last, Indices: bp.indices})
+	}
+	return answer
+}
+
+// controller serves the status of a search over HTTP, and lets it be
+// paused, resumed, checkpointed and stopped. The best schedule in the
+// status is placed in the weeks of the season searched, as export does.
+type controller struct {
+	requests chan controlRequest
+	finished chan struct{}
+	cancel   context.CancelFunc
+	list     fixtures.FixtureWeekList
+}
+
+func newController(cancel context.CancelFunc, list fixtures.FixtureWeekList) *controller {
+	return &controller{
+		requests: make(chan controlRequest),
+		finished: make(chan struct{}),
+		cancel:   cancel,
+		list:     list,
+	}
+}
+
+func (c *controller) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", c.get(c.serveStatus))
+	mux.HandleFunc("/metrics", c.get(c.serveMetrics))
+	mux.HandleFunc("/pause", c.post(func(w http.ResponseWriter, r *http.Request) {
+		searchGate.pause()
+		log.Printf("Search paused")
+		c.serveStatus(w, r)
+	}))
+	mux.HandleFunc("/resume", c.post(func(w http.ResponseWriter, r *http.Request) {
+		searchGate.resume()
+		log.Printf("Search resumed")
+		c.serveStatus(w, r)
+	}))
+	mux.HandleFunc("/checkpoint", c.post(func(w http.ResponseWriter, r *http.Request) {
+		c.respond(w, r, true)
+	}))
+	mux.HandleFunc("/stop", c.post(func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("Stop requested over HTTP, stopping after writing the final checkpoint")
+		c.cancel()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	return mux
+}
+
+func (c *controller) get(f http.HandlerFunc) http.HandlerFunc {
+	return c.method(http.MethodGet, f)
+}
+
+func (c *controller) post(f http.HandlerFunc) http.HandlerFunc {
+	return c.method(http.MethodPost, f)
+}
+
+func (c *controller) method(method string, f http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			http.Error(w, fmt.Sprintf("%s only", method), http.StatusMethodNotAllowed)
+			return
+		}
+		f(w, r)
+	}
+}
+
+// status asks processResults for the status of the search, and returns
+// false if the search has finished or the request was abandoned.
+func (c *controller) status(r *http.Request, checkpoint bool) (searchStatus, bool) {
+	reply := make(chan searchStatus, 1)
+	select {
+	case c.requests <- controlRequest{checkpoint: checkpoint, reply: reply}:
+		return <-reply, true
+	case <-c.finished:
+	case <-r.Context().Done():
+	}
+	return searchStatus{}, false
+}
+
+func (c *controller) serveStatus(w http.ResponseWriter, r *http.Request) {
+	c.respond(w, r, false)
+}
+
+func (c *controller) respond(w http.ResponseWriter, r *http.Request, checkpoint bool) {
+	status, ok := c.status(r, checkpoint)
+	if !ok {
+		http.Error(w, "the search has finished", http.StatusServiceUnavailable)
+		return
+	}
+	if schedule, err := fixtures.ReadSchedule(bestFile); err == nil {
+		if located, err := c.list.Locate(schedule); err == nil {
+			var buffer bytes.Buffer
+			if fixtures.WriteScheduleJSON(&buffer, located) == nil {
+				status.BestSchedule = buffer.Bytes()
+			}
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(status)
+}
+
+// serveMetrics writes the status in the Prometheus text format.
+func (c *controller) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	status, ok := c.status(r, false)
+	if !ok {
+		http.Error(w, "the search has finished", http.StatusServiceUnavailable)
+		return
+	}
+	var b bytes.Buffer
+	metric := func(name string, kind string, help string, value float64) {
+		fmt.Fprintf(&b, "# HELP fixtures_%s %s\n# TYPE fixtures_%s %s\nfixtures_%s %g\n", name, help, name, kind, name, value)
+	}
+	metric("combinations_evaluated_total", "counter", "Combinations evaluated by the search.", float64(status.Evaluated))
+	metric("combinations_per_second", "gauge", "Combinations evaluated per second.", status.PerSecond)
+	metric("best_score_improvements_total", "counter", "Times the search has improved on the best score.", float64(status.Improvements))
+	if status.BestScore != nil {
+		metric("best_score_worst_team", "gauge", "Score of the worst team in the best schedule.", float64(status.BestScore.Worst()))
+	}
+	paused := 0.0
+	if status.State == "paused" {
+		paused = 1
+	}
+	metric("search_paused", "gauge", "Whether the search is paused.", paused)
+	if status.Complete != nil {
+		metric("search_complete_ratio", "gauge", "Fraction of the space of schedules searched.", *status.Complete)
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.Write(b.Bytes())
+}
+
+// serve runs the HTTP server until the search has finished.
+func (c *controller) serve(address string) {
+	server := &http.Server{Addr: address, Handler: c.handler()}
+	go func() {
+		<-c.finished
+		server.Close()
+	}()
+	log.Printf("Serving the status of the search at http://%s/status", address)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Printf("HTTP server stopped: %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fixtures/fixtures"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGate(t *testing.T) {
+	g := newGate()
+	ctx, cancel := context.WithCancel(context.Background())
+	g.wait(ctx)
+	g.pause()
+	assert.True(t, g.isPaused())
+	waited := make(chan struct{})
+	go func() {
+		g.wait(ctx)
+		close(waited)
+	}()
+	select {
+	case <-waited:
+		t.Fatal("wait returned while the gate was paused")
+	case <-time.After(10 * time.Millisecond):
+	}
+	g.resume()
+	<-waited
+	assert.False(t, g.isPaused())
+	assert.True(t, g.pausedFor() >= 10*time.Millisecond)
+	g.pause()
+	cancel()
+	g.wait(ctx)
+}
+
+func TestController(t *testing.T) {
+	defer searchGate.resume()
+	ctx, cancel := context.WithCancel(context.Background())
+	c := newController(cancel, nil)
+	checkpoints := 0
+	done, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case request := <-c.requests:
+				if request.checkpoint {
+					checkpoints++
+				}
+				request.reply <- searchStatus{Mode: "anneal", State: "running", Evaluated: 42}
+			case <-done:
+				return
+			}
+		}
+	}()
+	server := httptest.NewServer(c.handler())
+	defer server.Close()
+	get := func(path string) (int, string) {
+		response, err := http.Get(server.URL + path)
+		assert.Nil(t, err)
+		defer response.Body.Close()
+		body, err := ioutil.ReadAll(response.Body)
+		assert.Nil(t, err)
+		return response.StatusCode, string(body)
+	}
+	post := func(path string) int {
+		response, err := http.Post(server.URL+path, "", nil)
+		assert.Nil(t, err)
+		response.Body.Close()
+		return response.StatusCode
+	}
+
+	code, body := get("/status")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, "\"evaluated\": 42")
+	code, body = get("/metrics")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, "fixtures_combinations_evaluated_total 42\n")
+	assert.NotContains(t, body, "fixtures_search_complete_ratio")
+	assert.NotContains(t, body, "fixtures_best_score_worst_team")
+	code, _ = get("/pause")
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+	assert.Equal(t, http.StatusOK, post("/pause"))
+	assert.True(t, searchGate.isPaused())
+	assert.Equal(t, http.StatusOK, post("/resume"))
+	assert.False(t, searchGate.isPaused())
+	assert.Equal(t, http.StatusOK, post("/checkpoint"))
+	assert.Equal(t, http.StatusAccepted, post("/stop"))
+	assert.NotNil(t, ctx.Err())
+	close(done)
+	<-stopped
+	assert.Equal(t, 1, checkpoints)
+	close(c.finished)
+	code, _ = get("/status")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+}
+
+func TestStatusLocatesTheBestSchedule(t *testing.T) {
+	saved := bestFile
+	defer func() { bestFile = saved }()
+	bestFile = filepath.Join(t.TempDir(), "best")
+	list := buildFixtureList(t)
+	schedule, _ := list.Iterator().Next()
+	assert.Nil(t, ioutil.WriteFile(bestFile, []byte("164\n"+schedule.String()), 0644))
+	located, err := list.Locate(schedule)
+	assert.Nil(t, err)
+	var exported bytes.Buffer
+	assert.Nil(t, fixtures.WriteScheduleJSON(&exported, located))
+
+	_, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := newController(cancel, list)
+	go func() {
+		request := <-c.requests
+		request.reply <- searchStatus{Mode: "exhaustive", State: "running"}
+	}()
+	server := httptest.NewServer(c.handler())
+	defer server.Close()
+	response, err := http.Get(server.URL + "/status")
+	assert.Nil(t, err)
+	defer response.Body.Close()
+	var status searchStatus
+	assert.Nil(t, json.NewDecoder(response.Body).Decode(&status))
+	var served, expected []map[string]interface{}
+	assert.Nil(t, json.Unmarshal(status.BestSchedule, &served))
+	assert.Nil(t, json.Unmarshal(exported.Bytes(), &expected))
+	assert.Equal(t, expected, served)
+	assert.Equal(t, "2018-09-30", served[0]["date"])
+	assert.NotEqual(t, "", served[0]["division"])
+}
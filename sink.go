@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CheckpointSink keeps the files of each checkpoint once they have been
+// written, so that the search can be resumed from them.
+type CheckpointSink interface {
+	Keep(files []string) error
+}
+
+var checkpointSinkNames = []string{"file", "git", "snapshot"}
+
+var sinkName = "file"
+var snapshotDir = ""
+var snapshotCount = 10
+
+var checkpointSink CheckpointSink = fileSink{}
+
+// newCheckpointSink returns the sink of the name given: file, to leave the
+// files where they are written; git, to commit them; or snapshot, to copy
+// them to a new directory within dir, keeping only the latest count.
+func newCheckpointSink(name string, dir string, count int) (CheckpointSink, error) {
+	switch name {
+	case "file":
+		return fileSink{}, nil
+	case "git":
+		return gitSink{}, nil
+	case "snapshot":
+		if count < 1 {
+			return nil, fmt.Errorf("the number of snapshots to keep must be at least 1")
+		}
+		return &snapshotSink{dir: dir, count: count}, nil
+	}
+	return nil, fmt.Errorf("unknown checkpoint sink %q: must be one of %s", name, strings.Join(checkpointSinkNames, ", "))
+}
+
+type fileSink struct{}
+
+func (fileSink) Keep(files []string) error {
+	return nil
+}
+
+// gitSink commits the files to the git repository that holds them.
+type gitSink struct{}
+
+func (gitSink) Keep(files []string) error {
+	if err := runGit(append([]string{"add", "--"}, files...)...); err != nil {
+		return err
+	}
+	if exec.Command("git", append([]string{"diff", "--cached", "--quiet", "--"}, files...)...).Run() == nil {
+		return nil
+	}
+	return runGit(append([]string{"commit", "-m", "Latest status", "--"}, files...)...)
+}
+
+func runGit(args ...string) error {
+	output, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git %s failed: %v: %s", args[0], err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// snapshotSink copies the files to a directory, named from the time, within
+// dir, and removes the oldest such directories so that only count remain.
+type snapshotSink struct {
+	dir   string
+	count int
+}
+
+const snapshotPrefix = "snapshot-"
+const snapshotTimeFormat = "20060102T150405.000000000"
+
+func (s *snapshotSink) Keep(files []string) error {
+	target := filepath.Join(s.dir, snapshotPrefix+time.Now().UTC().Format(snapshotTimeFormat))
+	if err := os.MkdirAll(target, 0755); err != nil {
+		return err
+	}
+	for _, f := range files {
+		data, err := ioutil.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		if err := writeFileAtomic(filepath.Join(target, filepath.Base(f)), data); err != nil {
+			return err
+		}
+	}
+	return s.prune()
+}
+
+func (s *snapshotSink) prune() error {
+	entries, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	snapshots := make([]string, 0)
+	for _, e := range entries {
+		if e.IsDir() && strings.HasPrefix(e.Name(), snapshotPrefix) {
+			snapshots = append(snapshots, e.Name())
+		}
+	}
+	sort.Strings(snapshots)
+	for len(snapshots) > s.count {
+		if err := os.RemoveAll(filepath.Join(s.dir, snapshots[0])); err != nil {
+			return err
+		}
+		snapshots = snapshots[1:]
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewCheckpointSink(t *testing.T) {
+	sink, err := newCheckpointSink("file", "", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, fileSink{}, sink)
+	sink, err = newCheckpointSink("snapshot", "snapshots", 3)
+	assert.Nil(t, err)
+	assert.Equal(t, &snapshotSink{dir: "snapshots", count: 3}, sink)
+	_, err = newCheckpointSink("snapshot", "snapshots", 0)
+	assert.EqualError(t, err, "the number of snapshots to keep must be at least 1")
+	_, err = newCheckpointSink("svn", "", 0)
+	assert.EqualError(t, err, "unknown checkpoint sink \"svn\": must be one of file, git, snapshot")
+}
+
+func TestSnapshotSink(t *testing.T) {
+	dir := t.TempDir()
+	best := filepath.Join(dir, "best")
+	snapshots := filepath.Join(dir, "snapshots")
+	sink := &snapshotSink{dir: snapshots, count: 2}
+	for _, content := range []string{"1", "2", "3"} {
+		assert.Nil(t, ioutil.WriteFile(best, []byte(content), 0644))
+		assert.Nil(t, sink.Keep([]string{best}))
+	}
+	entries, _ := ioutil.ReadDir(snapshots)
+	assert.Equal(t, 2, len(entries))
+	data, _ := ioutil.ReadFile(filepath.Join(snapshots, entries[1].Name(), "best"))
+	assert.Equal(t, "3", string(data))
+	assert.NotNil(t, sink.Keep([]string{filepath.Join(dir, "missing")}))
+}
+
+func TestGitSink(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(dir)
+	assert.NotNil(t, gitSink{}.Keep([]string{"best"}))
+	assert.Nil(t, runGit("init", "-q"))
+	runGit("config", "user.email", "fixtures@example.com")
+	runGit("config", "user.name", "Fixtures")
+	assert.Nil(t, ioutil.WriteFile("best", []byte("1"), 0644))
+	assert.Nil(t, ioutil.WriteFile("report", []byte("1"), 0644))
+	assert.Nil(t, gitSink{}.Keep([]string{"best", "report"}))
+	assert.Nil(t, gitSink{}.Keep([]string{"best", "report"}))
+	output, err := exec.Command("git", "log", "--oneline").Output()
+	assert.Nil(t, err)
+	assert.Regexp(t, "^[0-9a-f]+ Latest status\n$", string(output))
+	output, err = exec.Command("git", "ls-files").Output()
+	assert.Nil(t, err)
+	assert.Equal(t, "best\nreport\n", string(output))
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fixtures/fixtures"
+	"flag"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+var modelFile = "model.lp"
+var solverName = "auto"
+
+func solverFlags(fs *flag.FlagSet) {
+	fs.StringVar(&modelFile, "model", modelFile, "file to which the milp mode writes its model")
+	fs.StringVar(&solverName, "solver", solverName, "solver used by the milp mode: highs, cbc, or auto to use whichever is installed")
+}
+
+var solverCommands = map[string]func(model string, solution string) []string{
+	"highs": func(model string, solution string) []string {
+		return []string{"highs", "--model_file", model, "--solution_file", solution}
+	},
+	"cbc": func(model string, solution string) []string {
+		return []string{"cbc", model, "solve", "solu", solution}
+	},
+}
+
+func solveModel(list fixtures.FixtureWeekList) {
+	f, err := os.Create(modelFile)
+	if err != nil {
+		log.Fatalf("File %s could not be created: %v", modelFile, err)
+	}
+	err = list.WriteLP(f, scoringConfig)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		log.Fatalf("File %s could not be written: %v", modelFile, err)
+	}
+	log.Printf("Model written to file %s", modelFile)
+	solutionFile := strings.TrimSuffix(modelFile, ".lp") + ".sol"
+	command := findSolver(modelFile, solutionFile)
+	if command == nil {
+		log.Printf("No solver found; solve the model yourself, or install HiGHS or CBC")
+		return
+	}
+	log.Printf("Running %s", strings.Join(command, " "))
+	if output, err := exec.Command(command[0], command[1:]...).CombinedOutput(); err != nil {
+		log.Fatalf("Solver failed: %v\n%s", err, output)
+	}
+	sf, err := os.Open(solutionFile)
+	if err != nil {
+		log.Fatalf("Solution could not be read: %v", err)
+	}
+	defer sf.Close()
+	schedule, err := list.ReadSolution(sf)
+	if err != nil {
+		log.Fatalf("Solution in file %s is not valid: %v", solutionFile, err)
+	}
+	score := schedule.ScoreWith(scorer)
+	log.Printf("The solver's schedule scores %v", score)
+	if best := getBestScore(); score.Better(best) {
+		writeBest(schedule, score)
+		log.Printf("Found a better score: %v (was %v)", score, best)
+	}
+}
+
+func findSolver(model string, solution string) []string {
+	names := []string{solverName}
+	if solverName == "auto" {
+		names = []string{"highs", "cbc"}
+	}
+	for _, name := range names {
+		command, known := solverCommands[name]
+		if !known {
+			log.Fatalf("Unknown solver %q", name)
+		}
+		if _, err := exec.LookPath(name); err == nil {
+			return command(model, solution)
+		}
+	}
+	return nil
+}